@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+)
+
+// ABCSize holds the assignment, branch and condition counts of a function and their combined
+// score, sqrt(A² + B² + C²). Unlike LOC, it does not change when the same code is formatted
+// over more or fewer lines.
+type ABCSize struct {
+	Assignments int     `json:"assignments"`
+	Branches    int     `json:"branches"`
+	Conditions  int     `json:"conditions"`
+	Score       float64 `json:"score"`
+}
+
+// CalculateABC measures the ABC size of a function body, including its function literals.
+// Assignments count every non-blank name assigned by =, :=, an assignment operator, ++, --,
+// an initialized var declaration or a range clause. Branches count function calls. Conditions
+// count comparison operators, else branches and the clauses of switch and select statements.
+func CalculateABC(n ast.Node) ABCSize {
+	var abc ABCSize
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			abc.Assignments += countAssigned(node.Lhs)
+		case *ast.IncDecStmt:
+			abc.Assignments++
+		case *ast.RangeStmt:
+			abc.Assignments += countAssigned([]ast.Expr{node.Key, node.Value})
+		case *ast.ValueSpec:
+			if len(node.Values) > 0 {
+				for _, name := range node.Names {
+					if name.Name != "_" {
+						abc.Assignments++
+					}
+				}
+			}
+		case *ast.CallExpr:
+			abc.Branches++
+		case *ast.BinaryExpr:
+			switch node.Op {
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+				abc.Conditions++
+			}
+		case *ast.IfStmt:
+			if node.Else != nil {
+				abc.Conditions++
+			}
+		case *ast.CaseClause, *ast.CommClause:
+			abc.Conditions++
+		}
+		return true
+	})
+	abc.Score = math.Sqrt(float64(abc.Assignments*abc.Assignments + abc.Branches*abc.Branches + abc.Conditions*abc.Conditions))
+	return abc
+}
+
+// countAssigned counts the assignment targets that are not the blank identifier
+func countAssigned(exprs []ast.Expr) int {
+	count := 0
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name == "_" {
+			continue
+		}
+		count++
+	}
+	return count
+}
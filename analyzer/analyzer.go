@@ -5,6 +5,7 @@ import (
 	"go/parser"
 	"go/scanner"
 	"go/token"
+	"io/fs"
 	"math"
 	"os"
 	"strings"
@@ -12,129 +13,435 @@ import (
 
 // MethodResult holds the analysis results for each function.
 type MethodResult struct {
-	MethodName           string
-	Cyclomatic           int
-	HalsteadVolume       float64
-	LOC                  int
-	MaintainabilityIndex float64
+	MethodName           string          `json:"name"`
+	Receiver             string          `json:"receiver,omitempty"`
+	Package              string          `json:"package"`
+	Line                 int             `json:"line"`
+	EndLine              int             `json:"endLine"`
+	Cyclomatic           int             `json:"cyclomatic"`
+	NPath                int64           `json:"npath"`
+	ABC                  ABCSize         `json:"abc"`
+	MaxNesting           int             `json:"maxNesting"`
+	HalsteadVolume       float64         `json:"halsteadVolume"`
+	Halstead             HalsteadMetrics `json:"halstead"`
+	LOC                  int             `json:"loc"`
+	LLOC                 int             `json:"lloc"`
+	Indentation          int             `json:"indentation"`
+	CodeLines            int             `json:"codeLines"`
+	CommentLines         int             `json:"commentLines"`
+	BlankLines           int             `json:"blankLines"`
+	CommentDensity       float64         `json:"commentDensity"`
+	FileCommentDensity   float64         `json:"fileCommentDensity"`
+	LocalVars            int             `json:"localVars"`
+	Params               int             `json:"params"`
+	Results              int             `json:"results"`
+	Exits                int             `json:"exits"`
+	MagicNumbers         int             `json:"magicNumbers"`
+	ErrorHandling        ErrorHandling   `json:"errorHandling"`
+	Concurrency          Concurrency     `json:"concurrency"`
+	Goroutines           int             `json:"goroutines"`
+	Defers               int             `json:"defers"`
+	Panics               int             `json:"panics"`
+	Recovers             int             `json:"recovers"`
+	MaintainabilityIndex float64         `json:"maintainabilityIndex"`
+	ReturnsError         bool            `json:"returnsError"`
+	PanicLines           []int           `json:"panicLines,omitempty"`
+	// IsGodFunction is set by the caller when the function is bad on several axes at once.
+	IsGodFunction bool `json:"isGodFunction"`
+	// FanIn and FanOut are set by the caller when type information is available, see CalculateFanOut.
+	FanIn  int `json:"fanIn"`
+	FanOut int `json:"fanOut"`
+}
+
+// Options controls how source files are parsed and measured.
+type Options struct {
+	// CommentDensityMultiplier scales the comment density bonus of the Maintainability Index.
+	CommentDensityMultiplier float64
+	// ParserMode is OR'd into parser.ParseComments, e.g. parser.SkipObjectResolution or parser.DeclarationErrors.
+	ParserMode parser.Mode
+	// HalsteadProfile selects the operator classification; empty means HalsteadClassic.
+	HalsteadProfile HalsteadProfile
+	// MIVariant selects the Maintainability Index formula; empty means MIZeds.
+	MIVariant MIVariant
+	// MagicNumberAllowlist lists the numbers that are not magic numbers; nil means DefaultMagicNumberAllowlist.
+	MagicNumberAllowlist []float64
+	// TabWidth is the display width of a tab, used by the indentation metric and FindLongLines; 0 means DefaultTabWidth.
+	TabWidth int
+}
+
+// DefaultTabWidth is the display width of a tab when Options sets none, Go's convention of 8 columns.
+const DefaultTabWidth = 8
+
+// tabWidth returns the tab width of the options, DefaultTabWidth when unset
+func (o Options) tabWidth() int {
+	if o.TabWidth > 0 {
+		return o.TabWidth
+	}
+	return DefaultTabWidth
+}
+
+// QualifiedName returns the function name prefixed with its receiver type for methods.
+func (r MethodResult) QualifiedName() string {
+	if r.Receiver != "" {
+		return r.Receiver + "." + r.MethodName
+	}
+	return r.MethodName
+}
+
+// IsExported reports whether the function is part of the public API. Methods additionally
+// require their receiver type to be exported.
+func (r MethodResult) IsExported() bool {
+	if !ast.IsExported(r.MethodName) {
+		return false
+	}
+	return r.Receiver == "" || ast.IsExported(r.Receiver)
+}
+
+// ReceiverTypeName returns the name of the receiver type of a method, or an empty string for plain functions.
+func ReceiverTypeName(fn *ast.FuncDecl) string {
+	if ident := receiverTypeIdent(fn); ident != nil {
+		return ident.Name
+	}
+	return ""
+}
+
+// receiverTypeIdent returns the identifier naming the receiver type of a method, or nil
+func receiverTypeIdent(fn *ast.FuncDecl) *ast.Ident {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return nil
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	// Strip type parameters from generic receivers such as List[T].
+	switch x := expr.(type) {
+	case *ast.IndexExpr:
+		expr = x.X
+	case *ast.IndexListExpr:
+		expr = x.X
+	}
+	ident, _ := expr.(*ast.Ident)
+	return ident
 }
 
 // CalculateCyclomaticComplexity calculates the cyclomatic complexity for a given AST node.
 func CalculateCyclomaticComplexity(n ast.Node) int {
 	complexity := 1
+	inspectDecisionPoints(n, func(ast.Node, string) {
+		complexity++
+	})
+	return complexity
+}
+
+// DecisionPoint is a construct that adds one to the cyclomatic complexity of a function
+type DecisionPoint struct {
+	Line int
+	// Kind is the keyword or operator of the decision: if, for, range, case, default, && or ||
+	Kind string
+}
+
+// FindDecisionPoints returns the decision points of a node in source order. The cyclomatic
+// complexity of the node is their number plus one.
+func FindDecisionPoints(fset *token.FileSet, n ast.Node) []DecisionPoint {
+	var points []DecisionPoint
+	inspectDecisionPoints(n, func(node ast.Node, kind string) {
+		pos := node.Pos()
+		if expr, ok := node.(*ast.BinaryExpr); ok {
+			pos = expr.OpPos
+		}
+		points = append(points, DecisionPoint{Line: fset.Position(pos).Line, Kind: kind})
+	})
+	return points
+}
+
+// inspectDecisionPoints calls visit for each decision point of a node
+func inspectDecisionPoints(n ast.Node, visit func(node ast.Node, kind string)) {
 	ast.Inspect(n, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.IfStmt:
-			complexity++
+			visit(node, "if")
 		case *ast.ForStmt:
-			complexity++
+			visit(node, "for")
 		case *ast.RangeStmt:
-			complexity++
+			visit(node, "range")
 		case *ast.CaseClause:
 			// Count each case clause
-			complexity++
+			if node.List == nil {
+				visit(node, "default")
+			} else {
+				visit(node, "case")
+			}
 		case *ast.BinaryExpr:
 			// Count logical operators && and ||
 			if node.Op == token.LAND || node.Op == token.LOR {
-				complexity++
+				visit(node, node.Op.String())
 			}
 		}
 		return true
 	})
-	return complexity
 }
 
-// CalculateHalsteadVolume computes a simplified Halstead Volume based on operator and operand counts.
-func CalculateHalsteadVolume(src string) float64 {
+// CalculateLocalVars counts the local variables declared in a function body through
+// `var` declarations, short variable declarations and range clauses. Names that a short
+// variable declaration merely reassigns (such as err in `v, err := g()` after an earlier
+// err in the same scope) are not counted, while shadowing in an inner scope is.
+func CalculateLocalVars(n ast.Node) int {
+	count := 0
+	declares := func(ident *ast.Ident, decl ast.Node) bool {
+		if ident.Name == "_" {
+			return false
+		}
+		// Without object resolution every name is treated as a new declaration.
+		return ident.Obj == nil || ident.Obj.Decl == decl
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE {
+				break
+			}
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && declares(ident, node) {
+					count++
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Tok != token.DEFINE {
+				break
+			}
+			for _, expr := range []ast.Expr{node.Key, node.Value} {
+				if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+					count++
+				}
+			}
+		case *ast.DeclStmt:
+			if gen, ok := node.Decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				for _, spec := range gen.Specs {
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						if name.Name != "_" {
+							count++
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// CountGoAndDefer counts the go and defer statements in a function body, including those in function literals.
+func CountGoAndDefer(n ast.Node) (goroutines, defers int) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.GoStmt:
+			goroutines++
+		case *ast.DeferStmt:
+			defers++
+		}
+		return true
+	})
+	return goroutines, defers
+}
+
+// CalculateLOC returns the number of lines in the source code.
+func CalculateLOC(src string) int {
+	return len(strings.Split(src, "\n"))
+}
+
+// CalculateLLOC returns the logical lines of code of a function body: the number of statements,
+// including those of its function literals. Blocks, empty statements, labels and the case clauses
+// of switch and select statements are not counted, so the figure does not depend on formatting.
+func CalculateLLOC(body *ast.BlockStmt) int {
+	lloc := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.EmptyStmt, *ast.LabeledStmt, *ast.CaseClause, *ast.CommClause:
+		case ast.Stmt:
+			lloc++
+		}
+		return true
+	})
+	return lloc
+}
+
+// CalculateIndentation returns the indentation complexity of a function body, given as the source
+// from its opening to its closing brace: the sum of the indentation levels of its non-blank lines,
+// relative to the line of the closing brace. A level is tabWidth columns, so a tab is one level, as
+// are tabWidth spaces. The metric only looks at whitespace, so it serves as a proxy for structural
+// complexity that needs no parsing.
+func CalculateIndentation(body string, tabWidth int) int {
+	lines := strings.Split(body, "\n")
+	if len(lines) < 3 || tabWidth <= 0 {
+		return 0
+	}
+	base := indentLevel(lines[len(lines)-1], tabWidth)
+	total := 0
+	for _, line := range lines[1 : len(lines)-1] {
+		if strings.TrimSpace(line) != "" {
+			total += max(indentLevel(line, tabWidth)-base, 0)
+		}
+	}
+	return total
+}
+
+// indentLevel returns the indentation level of a line, the display width of its leading
+// whitespace in whole tabs
+func indentLevel(line string, tabWidth int) int {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	return DisplayWidth(indent, tabWidth) / tabWidth
+}
+
+// CountFunctions returns the number of functions and methods with a body declared in Go source,
+// or 0 when the source does not parse.
+func CountFunctions(src []byte) int {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// CountLines classifies the lines of a source fragment into code, comment and blank lines.
+// A line holding both code and a trailing comment counts as code.
+func CountLines(src string) (code, comment, blank int) {
 	var s scanner.Scanner
 	fset := token.NewFileSet()
 	file := fset.AddFile("", fset.Base(), len(src))
 	s.Init(file, []byte(src), nil, scanner.ScanComments)
 
-	totalOperators := 0
-	totalOperands := 0
-	uniqueOperators := make(map[string]bool)
-	uniqueOperands := make(map[string]bool)
-
-	// Define tokens considered as operators.
-	operatorTokens := map[token.Token]bool{
-		token.ADD:            true,
-		token.SUB:            true,
-		token.MUL:            true,
-		token.QUO:            true,
-		token.REM:            true,
-		token.AND:            true,
-		token.OR:             true,
-		token.XOR:            true,
-		token.SHL:            true,
-		token.SHR:            true,
-		token.AND_NOT:        true,
-		token.ADD_ASSIGN:     true,
-		token.SUB_ASSIGN:     true,
-		token.MUL_ASSIGN:     true,
-		token.QUO_ASSIGN:     true,
-		token.REM_ASSIGN:     true,
-		token.AND_ASSIGN:     true,
-		token.OR_ASSIGN:      true,
-		token.XOR_ASSIGN:     true,
-		token.SHL_ASSIGN:     true,
-		token.SHR_ASSIGN:     true,
-		token.AND_NOT_ASSIGN: true,
-		token.EQL:            true,
-		token.LSS:            true,
-		token.GTR:            true,
-		token.ASSIGN:         true,
-		token.NOT:            true,
-		token.NEQ:            true,
-		token.LEQ:            true,
-		token.GEQ:            true,
-		token.LAND:           true,
-		token.LOR:            true,
-		token.DEFINE:         true,
-	}
-
+	codeLines := make(map[int]bool)
+	commentLines := make(map[int]bool)
 	for {
-		_, tok, lit := s.Scan()
+		pos, tok, lit := s.Scan()
 		if tok == token.EOF {
 			break
 		}
-
-		if operatorTokens[tok] {
-			totalOperators++
-			key := lit
-			if key == "" {
-				key = tok.String()
-			}
-			uniqueOperators[key] = true
-		} else if tok == token.IDENT || tok == token.INT || tok == token.FLOAT ||
-			tok == token.IMAG || tok == token.CHAR || tok == token.STRING {
-			totalOperands++
-			key := lit
-			if key == "" {
-				key = tok.String()
+		// Skip semicolons inserted automatically at line ends.
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		// Block comments and raw strings may span several lines.
+		line := file.Line(pos)
+		for l := line; l <= line+strings.Count(lit, "\n"); l++ {
+			if tok == token.COMMENT {
+				commentLines[l] = true
+			} else {
+				codeLines[l] = true
 			}
-			uniqueOperands[key] = true
 		}
 	}
 
-	vocabulary := len(uniqueOperators) + len(uniqueOperands)
-	length := totalOperators + totalOperands
-	var volume float64
-	if vocabulary > 0 {
-		volume = float64(length) * math.Log2(float64(vocabulary))
-	} else {
-		volume = 0
+	for i, text := range strings.Split(src, "\n") {
+		switch line := i + 1; {
+		case codeLines[line]:
+			code++
+		case commentLines[line]:
+			comment++
+		case strings.TrimSpace(text) == "":
+			blank++
+		default:
+			code++
+		}
 	}
-	return volume
+	return code, comment, blank
 }
 
-// CalculateLOC returns the number of lines in the source code.
-func CalculateLOC(src string) int {
-	return len(strings.Split(src, "\n"))
+// DisplayWidth returns the rendered width of a line, expanding tabs to the next multiple of tabWidth.
+func DisplayWidth(line string, tabWidth int) int {
+	width := 0
+	for _, r := range line {
+		if r == '\t' && tabWidth > 0 {
+			width += tabWidth - width%tabWidth
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// LongLine is a line of source wider than the limit given to FindLongLines.
+type LongLine struct {
+	// Line is the 1-based line number.
+	Line int
+	// Width is the display width of the line, with tabs expanded.
+	Width int
+	// Column is the 1-based position, in characters, of the first character past the limit.
+	Column int
+}
+
+// FindLongLines returns the lines of the source whose display width, with tabs expanded to the tab
+// width of the options, is beyond maxWidth.
+func FindLongLines(src []byte, maxWidth int, opts Options) []LongLine {
+	tabWidth := opts.tabWidth()
+	var long []LongLine
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimRight(line, "\r")
+		width := DisplayWidth(line, tabWidth)
+		if width <= maxWidth {
+			continue
+		}
+		column, shown := 0, 0
+		for _, r := range line {
+			column++
+			if r == '\t' {
+				shown += tabWidth - shown%tabWidth
+			} else {
+				shown++
+			}
+			if shown > maxWidth {
+				break
+			}
+		}
+		long = append(long, LongLine{Line: i + 1, Width: width, Column: column})
+	}
+	return long
+}
+
+// MIVariant selects the Maintainability Index formula. Tools report MI on different scales,
+// so the variant lets scores be compared with the tooling a team already uses.
+type MIVariant string
+
+const (
+	// MIZeds rescales the SEI base formula to 0-100 and adds a bonus scaled by the comment density multiplier.
+	MIZeds MIVariant = "zeds"
+	// MIOriginal is the original SEI formula with its 50·sin(√(2.4·CM)) comment term, unbounded and unscaled.
+	MIOriginal MIVariant = "original"
+	// MIVisualStudio is the Visual Studio formula: the SEI base formula rescaled to 0-100, without a comment term.
+	MIVisualStudio MIVariant = "visualstudio"
+)
+
+// MIVariants lists the available Maintainability Index variants.
+var MIVariants = []MIVariant{MIZeds, MIOriginal, MIVisualStudio}
+
+// IsValid reports whether the variant is one of the available variants. The empty variant is valid and means zeds.
+func (v MIVariant) IsValid() bool {
+	if v == "" {
+		return true
+	}
+	for _, variant := range MIVariants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
 }
 
 // CalculateMaintainabilityIndex computes the Maintainability Index (MI) using a standard formula and a bonus from comment density.
 func CalculateMaintainabilityIndex(cyclomatic int, halsteadVolume float64, loc int, commentDensity float64, commentDensityMultiplier float64) float64 {
+	return CalculateMaintainabilityIndexWithVariant(MIZeds, cyclomatic, halsteadVolume, loc, commentDensity, commentDensityMultiplier)
+}
+
+// CalculateMaintainabilityIndexWithVariant computes the Maintainability Index with the given formula variant.
+// The comment density multiplier only applies to the zeds variant.
+func CalculateMaintainabilityIndexWithVariant(variant MIVariant, cyclomatic int, halsteadVolume float64, loc int, commentDensity float64, commentDensityMultiplier float64) float64 {
 	safeVolume := halsteadVolume
 	if safeVolume <= 0 {
 		safeVolume = 1
@@ -143,13 +450,15 @@ func CalculateMaintainabilityIndex(cyclomatic int, halsteadVolume float64, loc i
 	if safeLOC <= 0 {
 		safeLOC = 1
 	}
-	baseMI := (171 - 5.2*math.Log(safeVolume) - 0.23*float64(cyclomatic) - 16.2*math.Log(safeLOC)) * 100 / 171
-	bonus := commentDensityMultiplier * math.Sin(math.Sqrt(2.4*commentDensity))
-	mi := baseMI + bonus
-	if mi < 0 {
-		mi = 0
+	base := 171 - 5.2*math.Log(safeVolume) - 0.23*float64(cyclomatic) - 16.2*math.Log(safeLOC)
+	commentTerm := math.Sin(math.Sqrt(2.4 * commentDensity))
+	switch variant {
+	case MIOriginal:
+		return base + 50*commentTerm
+	case MIVisualStudio:
+		return math.Max(0, base*100/171)
 	}
-	return mi
+	return math.Max(0, base*100/171+commentDensityMultiplier*commentTerm)
 }
 
 // CalculateCommentDensity computes the ratio of comment lines to total lines in the file.
@@ -167,23 +476,53 @@ func CalculateCommentDensity(fileContent string, comments []*ast.CommentGroup) f
 	return float64(commentLines) / float64(totalLines)
 }
 
-// AnalyzeMethods analyzes all functions in a given Go source file and computes code quality metrics.
+// CalculateFunctionCommentDensity computes the ratio of comment lines to total lines of a function,
+// counting its doc comment and the comments within its declaration.
+func CalculateFunctionCommentDensity(fset *token.FileSet, fn *ast.FuncDecl, comments []*ast.CommentGroup) float64 {
+	start := fn.Pos()
+	if fn.Doc != nil {
+		start = fn.Doc.Pos()
+	}
+	totalLines := fset.Position(fn.End()).Line - fset.Position(start).Line + 1
+	commentLines := 0
+	for _, cg := range comments {
+		if cg.Pos() < start || cg.End() > fn.End() {
+			continue
+		}
+		for _, comment := range cg.List {
+			commentLines += len(strings.Split(comment.Text, "\n"))
+		}
+	}
+	return float64(commentLines) / float64(totalLines)
+}
+
+// AnalyzeMethods analyzes all functions in the named Go source file of fsys and computes code quality metrics.
 // It returns the analysis results for each function and the global comment density.
-func AnalyzeMethods(filePath string, commentDensityMultiplier float64) ([]MethodResult, float64, error) {
-	data, err := os.ReadFile(filePath)
+// To analyze a file on disk, pass os.DirFS of its directory and the file's base name.
+func AnalyzeMethods(fsys fs.FS, name string, opts Options) ([]MethodResult, float64, error) {
+	data, err := fs.ReadFile(fsys, name)
 	if err != nil {
 		return nil, 0, err
 	}
-	source := string(data)
+	return AnalyzeSource(name, data, opts)
+}
+
+// AnalyzeSource analyzes Go source held in memory. The name is used for positions in error messages.
+func AnalyzeSource(name string, src []byte, opts Options) ([]MethodResult, float64, error) {
+	source := string(src)
 
 	fset := token.NewFileSet()
 	// Parse the file including comments.
-	f, err := parser.ParseFile(fset, filePath, source, parser.ParseComments)
+	f, err := parser.ParseFile(fset, name, source, parser.ParseComments|opts.ParserMode)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	globalCommentDensity := CalculateCommentDensity(source, f.Comments)
+	allowlist := opts.MagicNumberAllowlist
+	if allowlist == nil {
+		allowlist = DefaultMagicNumberAllowlist
+	}
 	var results []MethodResult
 
 	// Traverse the AST to find function declarations.
@@ -195,16 +534,49 @@ func AnalyzeMethods(filePath string, commentDensityMultiplier float64) ([]Method
 			funcSource := source[startOffset:endOffset]
 
 			cc := CalculateCyclomaticComplexity(fn.Body)
-			halstead := CalculateHalsteadVolume(funcSource)
+			halstead := CalculateHalstead(funcSource, opts.HalsteadProfile)
 			loc := CalculateLOC(funcSource)
-			mi := CalculateMaintainabilityIndex(cc, halstead, loc, globalCommentDensity, commentDensityMultiplier)
+			codeLines, commentLines, blankLines := CountLines(funcSource)
+			localVars := CalculateLocalVars(fn.Body)
+			goroutines, defers := CountGoAndDefer(fn.Body)
+			panicLines := FindPanicCalls(fset, fn.Body)
+			commentDensity := CalculateFunctionCommentDensity(fset, fn, f.Comments)
+			mi := CalculateMaintainabilityIndexWithVariant(opts.MIVariant, cc, halstead.Volume, loc, commentDensity, opts.CommentDensityMultiplier)
 
 			results = append(results, MethodResult{
 				MethodName:           funcName,
+				Receiver:             ReceiverTypeName(fn),
+				Package:              f.Name.Name,
+				Line:                 fset.Position(fn.Pos()).Line,
+				EndLine:              fset.Position(fn.End()).Line,
 				Cyclomatic:           cc,
-				HalsteadVolume:       halstead,
+				NPath:                NPathInt64(CalculateNPath(fn.Body)),
+				ABC:                  CalculateABC(fn.Body),
+				MaxNesting:           CalculateMaxNesting(fn.Body),
+				HalsteadVolume:       halstead.Volume,
+				Halstead:             halstead,
 				LOC:                  loc,
+				LLOC:                 CalculateLLOC(fn.Body),
+				Indentation:          CalculateIndentation(funcSource, opts.tabWidth()),
+				CodeLines:            codeLines,
+				CommentLines:         commentLines,
+				BlankLines:           blankLines,
+				CommentDensity:       commentDensity,
+				FileCommentDensity:   globalCommentDensity,
+				LocalVars:            localVars,
+				Params:               CountFields(fn.Type.Params),
+				Results:              CountFields(fn.Type.Results),
+				Exits:                CountExits(fn.Body),
+				MagicNumbers:         CountMagicNumbers(fn.Body, allowlist),
+				ErrorHandling:        CalculateErrorHandling(fset, fn.Body, loc),
+				Concurrency:          CalculateConcurrency(fn.Body),
+				Goroutines:           goroutines,
+				Defers:               defers,
+				Panics:               len(panicLines),
+				Recovers:             CountRecovers(fn.Body),
 				MaintainabilityIndex: mi,
+				ReturnsError:         ReturnsError(fn.Type),
+				PanicLines:           panicLines,
 			})
 		}
 	}
@@ -212,54 +584,91 @@ func AnalyzeMethods(filePath string, commentDensityMultiplier float64) ([]Method
 	return results, globalCommentDensity, nil
 }
 
-// AnalyzeFile analyzes a Go source file and returns its functions and methods
-func AnalyzeFile(filepath string) ([]string, error) {
-	// Create a new token set
-	fset := token.NewFileSet()
+// Declaration kinds
+const (
+	DeclFunction = "function"
+	DeclMethod   = "method"
+	DeclType     = "type"
+)
+
+// Declaration is a top-level function, method or type declared in a Go source file
+type Declaration struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Receiver is the receiver type of a method, without type parameters
+	Receiver        string `json:"receiver,omitempty"`
+	PointerReceiver bool   `json:"pointerReceiver,omitempty"`
+	// TypeKind is struct, interface or alias for types, and type for other type definitions
+	TypeKind string `json:"typeKind,omitempty"`
+	Line     int    `json:"line"`
+	EndLine  int    `json:"endLine"`
+	Exported bool   `json:"exported"`
+}
+
+// QualifiedName returns the declaration name, prefixed with its receiver type for methods,
+// e.g. (*Parser).Parse or Token.String
+func (d Declaration) QualifiedName() string {
+	switch {
+	case d.Receiver == "":
+		return d.Name
+	case d.PointerReceiver:
+		return "(*" + d.Receiver + ")." + d.Name
+	}
+	return d.Receiver + "." + d.Name
+}
 
-	// Parse the Go source file
+// AnalyzeFile reads a Go source file and returns its top-level functions, methods and types
+func AnalyzeFile(filepath string) ([]Declaration, error) {
 	data, err := os.ReadFile(filepath)
 	if err != nil {
 		return nil, err
 	}
+	return ListDeclarations(filepath, data, Options{})
+}
 
-	// Parse the file with its content
-	node, err := parser.ParseFile(fset, filepath, data, parser.ParseComments)
+// ListDeclarations returns the top-level functions, methods and types of Go source held in
+// memory, in source order.
+func ListDeclarations(name string, src []byte, opts Options) ([]Declaration, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, opts.ParserMode)
 	if err != nil {
 		return nil, err
 	}
 
-	var functions []string = make([]string, 0)
-
-	// Visit all nodes in AST
-	ast.Inspect(node, func(n ast.Node) bool {
-		switch x := n.(type) {
+	declarations := make([]Declaration, 0)
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
 		case *ast.FuncDecl:
-			name := x.Name.Name
-			if x.Recv != nil {
-				recv := x.Recv.List[0].Type
-				var recvName string
-				if star, ok := recv.(*ast.StarExpr); ok {
-					if ident, ok := star.X.(*ast.Ident); ok {
-						recvName = ident.Name
-					}
-				} else if ident, ok := recv.(*ast.Ident); ok {
-					recvName = ident.Name
+			declaration := Declaration{Kind: DeclFunction, Name: d.Name.Name, Line: fset.Position(d.Pos()).Line,
+				EndLine: fset.Position(d.End()).Line, Exported: d.Name.IsExported()}
+			if d.Recv != nil {
+				declaration.Kind = DeclMethod
+				declaration.Receiver = ReceiverTypeName(d)
+				_, declaration.PointerReceiver = d.Recv.List[0].Type.(*ast.StarExpr)
+				declaration.Exported = declaration.Exported && ast.IsExported(declaration.Receiver)
+			}
+			declarations = append(declarations, declaration)
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				declaration := Declaration{Kind: DeclType, Name: typeSpec.Name.Name, TypeKind: DeclType,
+					Line: fset.Position(typeSpec.Pos()).Line, EndLine: fset.Position(typeSpec.End()).Line,
+					Exported: typeSpec.Name.IsExported()}
+				switch typeSpec.Type.(type) {
+				case *ast.StructType:
+					declaration.TypeKind = KindStruct
+				case *ast.InterfaceType:
+					declaration.TypeKind = KindInterface
 				}
-				if recvName != "" {
-					functions = append(functions, "method: "+recvName+"."+name)
+				if typeSpec.Assign.IsValid() {
+					declaration.TypeKind = "alias"
 				}
-			} else {
-				functions = append(functions, "function: "+name)
+				declarations = append(declarations, declaration)
 			}
 		}
-		return true
-	})
-
-	// Eğer hiç fonksiyon bulunamadıysa boş slice döndür
-	if len(functions) == 0 {
-		return make([]string, 0), nil
 	}
-
-	return functions, nil
-}
\ No newline at end of file
+	return declarations, nil
+}
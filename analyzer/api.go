@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// APISurface counts the exported identifiers of a package, its public surface.
+type APISurface struct {
+	Functions int `json:"functions"`
+	// Methods counts the exported methods of exported types.
+	Methods   int `json:"methods"`
+	Types     int `json:"types"`
+	Variables int `json:"variables"`
+	Total     int `json:"total"`
+}
+
+// CalculateAPISurface counts the exported functions, methods, types and variables declared in the
+// files of a package. Methods of unexported types are left out, as other packages can only reach
+// them through an interface.
+func CalculateAPISurface(files []*ast.File) APISurface {
+	var api APISurface
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				if decl.Recv == nil {
+					api.Functions++
+				} else if ast.IsExported(ReceiverTypeName(decl)) {
+					api.Methods++
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() {
+							api.Types++
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if decl.Tok == token.VAR && name.IsExported() {
+								api.Variables++
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	api.Total = api.Functions + api.Methods + api.Types + api.Variables
+	return api
+}
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"go/build/constraint"
+	"strings"
+)
+
+// BuildConstraint returns the //go:build expression in the header of a Go source file,
+// or nil when the file has none. Only the comments before the package clause are considered.
+func BuildConstraint(src []byte) (constraint.Expr, error) {
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		if constraint.IsGoBuild(line) {
+			return constraint.Parse(line)
+		}
+	}
+	return nil, nil
+}
+
+// MatchesTags reports whether a build constraint is satisfied when exactly the given tags are set.
+// A nil constraint always matches.
+func MatchesTags(expr constraint.Expr, tags []string) bool {
+	if expr == nil {
+		return true
+	}
+	return expr.Eval(func(tag string) bool {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	})
+}
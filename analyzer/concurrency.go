@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Concurrency holds the concurrency primitives a function uses and their combined weight, the sum
+// of the counts. Concurrent code is hard to reason about beyond what its control flow shows, so
+// a high weight marks functions that deserve extra review attention.
+type Concurrency struct {
+	Goroutines int `json:"goroutines"`
+	ChannelOps int `json:"channelOps"`
+	Selects    int `json:"selects"`
+	MutexOps   int `json:"mutexOps"`
+	Weight     int `json:"weight"`
+}
+
+// mutexMethods lists the methods of sync.Mutex and sync.RWMutex
+var mutexMethods = map[string]bool{
+	"Lock": true, "Unlock": true, "TryLock": true,
+	"RLock": true, "RUnlock": true, "TryRLock": true,
+}
+
+// CalculateConcurrency measures the concurrency weight of a function body, including its function
+// literals. Channel operations count sends, receives and close calls. Mutex operations count calls
+// to methods named like those of sync.Mutex and sync.RWMutex, such as Lock and RUnlock, since the
+// analysis has no type information. Ranging over a channel is not detected for the same reason.
+func CalculateConcurrency(n ast.Node) Concurrency {
+	var c Concurrency
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			c.Goroutines++
+		case *ast.SendStmt:
+			c.ChannelOps++
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				c.ChannelOps++
+			}
+		case *ast.SelectStmt:
+			c.Selects++
+		case *ast.CallExpr:
+			switch fun := node.Fun.(type) {
+			case *ast.Ident:
+				if fun.Name == "close" {
+					c.ChannelOps++
+				}
+			case *ast.SelectorExpr:
+				if mutexMethods[fun.Sel.Name] {
+					c.MutexOps++
+				}
+			}
+		}
+		return true
+	})
+	c.Weight = c.Goroutines + c.ChannelOps + c.Selects + c.MutexOps
+	return c
+}
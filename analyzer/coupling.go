@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+	"sort"
+)
+
+// PackageMetrics holds Robert C. Martin's package metrics, which relate how a package depends on
+// other packages to how abstract it is.
+type PackageMetrics struct {
+	Path string `json:"path"`
+	// Afferent counts the analyzed packages that import the package (Ca).
+	Afferent int `json:"afferent"`
+	// Efferent counts the packages the package imports, outside the standard library (Ce).
+	Efferent int `json:"efferent"`
+	// Instability is Ce / (Ca + Ce): 0 for a package that only others depend on, 1 for one that
+	// depends on others but is not depended on.
+	Instability float64 `json:"instability"`
+	// ExportedTypes and AbstractTypes count the exported named types and the interfaces among them.
+	ExportedTypes int `json:"exportedTypes"`
+	AbstractTypes int `json:"abstractTypes"`
+	// Abstractness is the share of the exported types that are interfaces.
+	Abstractness float64 `json:"abstractness"`
+	// Distance is |A + I - 1|, how far the package is from the main sequence, where abstractness
+	// and stability are in balance.
+	Distance float64 `json:"distance"`
+	// Depth is the length of the longest import chain from the package within the analyzed packages.
+	Depth int `json:"depth"`
+	// Documentation is the documentation coverage of the exported identifiers of the package.
+	Documentation DocCoverage `json:"documentation"`
+	// API counts the exported identifiers of the package.
+	API APISurface `json:"api"`
+	// Imports splits the distinct imports of the package's files by origin.
+	Imports ImportProfile `json:"imports"`
+}
+
+// NewPackageMetrics derives the metrics of a package from its coupling and exported type counts.
+// A package without dependencies in either direction has an instability of 0, and one without
+// exported types an abstractness of 0.
+func NewPackageMetrics(path string, afferent, efferent, exportedTypes, abstractTypes int) PackageMetrics {
+	m := PackageMetrics{Path: path, Afferent: afferent, Efferent: efferent, ExportedTypes: exportedTypes, AbstractTypes: abstractTypes}
+	if afferent+efferent > 0 {
+		m.Instability = float64(efferent) / float64(afferent+efferent)
+	}
+	if exportedTypes > 0 {
+		m.Abstractness = float64(abstractTypes) / float64(exportedTypes)
+	}
+	m.Distance = math.Abs(m.Abstractness + m.Instability - 1)
+	return m
+}
+
+// CountExportedTypes counts the exported named types declared in the files of a package, and the
+// interfaces among them. Type aliases are not counted, as they declare no new type.
+func CountExportedTypes(files []*ast.File) (exported, abstract int) {
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if !typeSpec.Name.IsExported() || typeSpec.Assign.IsValid() {
+					continue
+				}
+				exported++
+				if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					abstract++
+				}
+			}
+		}
+	}
+	return exported, abstract
+}
+
+// ImportGraph maps each package path to the paths of the packages it imports, within a set of packages.
+type ImportGraph map[string][]string
+
+// ImportCycles returns the import cycles of the graph: the groups of packages that import each
+// other directly or indirectly, each sorted by path. The Go toolchain rejects such packages, but
+// a cycle must be found before it can be broken.
+func (g ImportGraph) ImportCycles() [][]string {
+	var cycles [][]string
+	for _, component := range g.components() {
+		if len(component) > 1 || g.importsItself(component[0]) {
+			sorted := append([]string(nil), component...)
+			sort.Strings(sorted)
+			cycles = append(cycles, sorted)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// DependencyDepths returns the dependency depth of each package: the length of the longest import
+// chain from it within the graph. A package that imports none of the others has a depth of 0, and
+// the packages of a cycle share a depth.
+func (g ImportGraph) DependencyDepths() map[string]int {
+	depths := make(map[string]int)
+	// Components come in reverse topological order, so the depths of the imported components are known.
+	for _, component := range g.components() {
+		inComponent := make(map[string]bool, len(component))
+		for _, path := range component {
+			inComponent[path] = true
+		}
+		depth := 0
+		for _, path := range component {
+			for _, imported := range g[path] {
+				if _, ok := g[imported]; ok && !inComponent[imported] {
+					depth = max(depth, depths[imported]+1)
+				}
+			}
+		}
+		for _, path := range component {
+			depths[path] = depth
+		}
+	}
+	return depths
+}
+
+// importsItself reports whether a package imports itself
+func (g ImportGraph) importsItself(path string) bool {
+	for _, imported := range g[path] {
+		if imported == path {
+			return true
+		}
+	}
+	return false
+}
+
+// components returns the strongly connected components of the graph with Tarjan's algorithm, in
+// reverse topological order: every component comes after the components it imports.
+func (g ImportGraph) components() [][]string {
+	paths := make([]string, 0, len(g))
+	for path := range g {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	var visit func(path string)
+	visit = func(path string) {
+		index[path] = len(index)
+		lowLink[path] = index[path]
+		stack = append(stack, path)
+		onStack[path] = true
+		for _, imported := range g[path] {
+			if _, ok := g[imported]; !ok {
+				continue
+			}
+			if _, visited := index[imported]; !visited {
+				visit(imported)
+				lowLink[path] = min(lowLink[path], lowLink[imported])
+			} else if onStack[imported] {
+				lowLink[path] = min(lowLink[path], index[imported])
+			}
+		}
+		if lowLink[path] != index[path] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == path {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+	for _, path := range paths {
+		if _, visited := index[path]; !visited {
+			visit(path)
+		}
+	}
+	return components
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// DefaultDebtMarkers lists the comment markers that note technical debt.
+var DefaultDebtMarkers = []string{"TODO", "FIXME", "HACK", "XXX"}
+
+// DebtMarker is a technical debt note found in a comment.
+type DebtMarker struct {
+	Marker string `json:"marker"`
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+}
+
+// FindDebtMarkers returns the debt notes in the comments of Go source, in source order. A marker
+// matches as a whole word, e.g. TODO matches "TODO:" and "TODO(alice)" but not "TODOS". Each comment
+// line holds at most one note, named after the first marker on it. The source does not need to
+// parse, as only its comments are scanned.
+func FindDebtMarkers(src []byte, markers []string) []DebtMarker {
+	if len(markers) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(markers))
+	for i, marker := range markers {
+		quoted[i] = regexp.QuoteMeta(marker)
+	}
+	pattern := regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b`)
+
+	var found []DebtMarker
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT {
+			continue
+		}
+		line := fset.Position(pos).Line
+		for i, text := range strings.Split(lit, "\n") {
+			if match := pattern.FindStringSubmatchIndex(text); match != nil {
+				found = append(found, DebtMarker{
+					Marker: text[match[2]:match[3]],
+					Line:   line + i,
+					Text:   strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text[match[0]:]), "*/")),
+				})
+			}
+		}
+	}
+	return found
+}
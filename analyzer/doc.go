@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// DocCoverage holds how many of the exported identifiers of a package have doc comments.
+type DocCoverage struct {
+	Exported   int `json:"exported"`
+	Documented int `json:"documented"`
+	// Percent is the share of the exported identifiers that are documented, 100 for a package
+	// without exported identifiers.
+	Percent float64 `json:"percent"`
+}
+
+// CalculateDocCoverage measures the documentation coverage of the files of a package. It counts
+// the exported functions, the exported methods of exported types, and the exported types, constants
+// and variables. A type, constant or variable declared in a group is documented by its own comment
+// or by the comment of the group, as go doc shows either.
+func CalculateDocCoverage(files []*ast.File) DocCoverage {
+	var dc DocCoverage
+	count := func(name *ast.Ident, docs ...*ast.CommentGroup) {
+		if !name.IsExported() {
+			return
+		}
+		dc.Exported++
+		for _, doc := range docs {
+			if doc != nil && len(doc.List) > 0 {
+				dc.Documented++
+				return
+			}
+		}
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if receiver := ReceiverTypeName(decl); decl.Recv == nil || ast.IsExported(receiver) {
+					count(decl.Name, decl.Doc)
+				}
+			case *ast.GenDecl:
+				if decl.Tok == token.IMPORT {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						count(spec.Name, spec.Doc, decl.Doc)
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							count(name, spec.Doc, decl.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+	dc.Percent = 100
+	if dc.Exported > 0 {
+		dc.Percent = float64(dc.Documented) / float64(dc.Exported) * 100
+	}
+	return dc
+}
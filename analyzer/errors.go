@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ErrorHandling holds how much of a function deals with errors. Error variables are recognized
+// by name, err or any name ending in Err such as readErr, since the analysis has no type information.
+type ErrorHandling struct {
+	// Checks counts the if statements comparing an error variable with nil.
+	Checks int `json:"checks"`
+	// Calls counts the calls whose results are assigned to an error variable.
+	Calls int `json:"calls"`
+	// CheckLines counts the lines of the error checks, from the if to the closing brace of its body.
+	CheckLines int `json:"checkLines"`
+	// Density is the share of the function's lines of code taken by error checks.
+	Density float64 `json:"density"`
+	// Ignored is set when errors are assigned but never checked, returned or otherwise used.
+	Ignored bool `json:"ignored"`
+}
+
+// CalculateErrorHandling measures the error handling of a function body, including its function
+// literals, over the given lines of code.
+func CalculateErrorHandling(fset *token.FileSet, body *ast.BlockStmt, loc int) ErrorHandling {
+	var eh ErrorHandling
+	assigned := make(map[*ast.Ident]bool)
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if errorAssignment(node.Lhs, node.Rhs, assigned) {
+				eh.Calls++
+			}
+		case *ast.ValueSpec:
+			names := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				names[i] = name
+			}
+			if errorAssignment(names, node.Values, assigned) {
+				eh.Calls++
+			}
+		case *ast.IfStmt:
+			if isErrorCheck(node.Cond) {
+				eh.Checks++
+				eh.CheckLines += fset.Position(node.Body.End()).Line - fset.Position(node.Pos()).Line + 1
+			}
+		case *ast.Ident:
+			if isErrorName(node.Name) && !assigned[node] {
+				used = true
+			}
+		}
+		return true
+	})
+	if loc > 0 {
+		eh.Density = float64(eh.CheckLines) / float64(loc)
+	}
+	eh.Ignored = eh.Calls > 0 && !used
+	return eh
+}
+
+// errorAssignment reports whether a call's results are assigned to an error variable, marking the
+// assigned error variables so that they do not count as uses
+func errorAssignment(lhs, rhs []ast.Expr, assigned map[*ast.Ident]bool) bool {
+	isCall := false
+	for _, expr := range rhs {
+		if _, ok := ast.Unparen(expr).(*ast.CallExpr); ok {
+			isCall = true
+		}
+	}
+	found := false
+	for _, expr := range lhs {
+		if ident, ok := expr.(*ast.Ident); ok && isErrorName(ident.Name) {
+			assigned[ident] = true
+			found = true
+		}
+	}
+	return isCall && found
+}
+
+// isErrorCheck reports whether a condition compares an error variable with nil, outside of function literals
+func isErrorCheck(cond ast.Expr) bool {
+	found := false
+	ast.Inspect(cond, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			if node.Op == token.EQL || node.Op == token.NEQ {
+				found = found || (isErrorIdent(node.X) && isNil(node.Y)) || (isNil(node.X) && isErrorIdent(node.Y))
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isErrorName reports whether a variable name follows the naming of error variables
+func isErrorName(name string) bool {
+	return name == "err" || strings.HasSuffix(name, "Err")
+}
+
+// isErrorIdent reports whether an expression is an error variable
+func isErrorIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && isErrorName(ident.Name)
+}
+
+// isNil reports whether an expression is the nil identifier
+func isNil(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// Callee returns the function or method statically called by a call expression, or nil for
+// builtins, type conversions and calls of function values. Calls of generic functions resolve
+// to the generic function rather than to its instantiation.
+func Callee(call *ast.CallExpr, info *types.Info) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	// Strip the type arguments of an instantiated generic function.
+	switch x := fun.(type) {
+	case *ast.IndexExpr:
+		fun = x.X
+	case *ast.IndexListExpr:
+		fun = x.X
+	}
+	var ident *ast.Ident
+	switch x := fun.(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	}
+	if ident == nil {
+		return nil
+	}
+	if fn, ok := info.Uses[ident].(*types.Func); ok {
+		return fn.Origin()
+	}
+	return nil
+}
+
+// CalculateFanOut returns the number of distinct functions and methods called in a function body,
+// including calls in its function literals. Callees are resolved with the type information of the
+// body's package, so calls to the same function through different names count once. Builtins, type
+// conversions and calls of function values are not counted.
+func CalculateFanOut(body *ast.BlockStmt, info *types.Info) int {
+	callees := make(map[*types.Func]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if fn := Callee(call, info); fn != nil {
+				callees[fn] = true
+			}
+		}
+		return true
+	})
+	return len(callees)
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"go/scanner"
+	"go/token"
+	"math"
+)
+
+// HalsteadProfile selects which tokens count as Halstead operators. Halstead implementations
+// legitimately differ here, so the profile lets volumes be aligned with a reference tool.
+type HalsteadProfile string
+
+const (
+	// HalsteadClassic counts arithmetic, bitwise, comparison, logical and assignment operators.
+	HalsteadClassic HalsteadProfile = "classic"
+	// HalsteadGoKeywords additionally counts Go keywords such as if, for and return as operators.
+	HalsteadGoKeywords HalsteadProfile = "go-keywords"
+	// HalsteadExtended additionally counts keywords, delimiters and function calls as operators.
+	HalsteadExtended HalsteadProfile = "extended"
+)
+
+// HalsteadProfiles lists the available Halstead profiles.
+var HalsteadProfiles = []HalsteadProfile{HalsteadClassic, HalsteadGoKeywords, HalsteadExtended}
+
+// IsValid reports whether the profile is one of the available profiles. The empty profile is valid and means classic.
+func (p HalsteadProfile) IsValid() bool {
+	if p == "" {
+		return true
+	}
+	for _, profile := range HalsteadProfiles {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// classicOperators are the tokens counted as operators by every profile.
+var classicOperators = map[token.Token]bool{
+	token.ADD:            true,
+	token.SUB:            true,
+	token.MUL:            true,
+	token.QUO:            true,
+	token.REM:            true,
+	token.AND:            true,
+	token.OR:             true,
+	token.XOR:            true,
+	token.SHL:            true,
+	token.SHR:            true,
+	token.AND_NOT:        true,
+	token.ADD_ASSIGN:     true,
+	token.SUB_ASSIGN:     true,
+	token.MUL_ASSIGN:     true,
+	token.QUO_ASSIGN:     true,
+	token.REM_ASSIGN:     true,
+	token.AND_ASSIGN:     true,
+	token.OR_ASSIGN:      true,
+	token.XOR_ASSIGN:     true,
+	token.SHL_ASSIGN:     true,
+	token.SHR_ASSIGN:     true,
+	token.AND_NOT_ASSIGN: true,
+	token.EQL:            true,
+	token.LSS:            true,
+	token.GTR:            true,
+	token.ASSIGN:         true,
+	token.NOT:            true,
+	token.NEQ:            true,
+	token.LEQ:            true,
+	token.GEQ:            true,
+	token.LAND:           true,
+	token.LOR:            true,
+	token.DEFINE:         true,
+}
+
+// scannedToken is a token of the scanned source together with its literal text
+type scannedToken struct {
+	tok token.Token
+	lit string
+}
+
+// isOperator reports whether the token counts as an operator under the profile.
+// next is the token that follows, used to recognize function calls.
+func (p HalsteadProfile) isOperator(t, next scannedToken) bool {
+	if classicOperators[t.tok] {
+		return true
+	}
+	switch p {
+	case HalsteadGoKeywords:
+		return t.tok.IsKeyword()
+	case HalsteadExtended:
+		if t.tok == token.IDENT && next.tok == token.LPAREN {
+			return true
+		}
+		// Closing delimiters are counted with their opening counterpart.
+		return t.tok.IsKeyword() || (t.tok.IsOperator() && t.tok != token.RPAREN && t.tok != token.RBRACK && t.tok != token.RBRACE)
+	}
+	return false
+}
+
+// isOperand reports whether the token is an identifier or a literal
+func isOperand(tok token.Token) bool {
+	return tok == token.IDENT || tok == token.INT || tok == token.FLOAT ||
+		tok == token.IMAG || tok == token.CHAR || tok == token.STRING
+}
+
+// HalsteadMetrics holds the Halstead software science measures of a piece of code.
+type HalsteadMetrics struct {
+	// DistinctOperators and DistinctOperands are n1 and n2, TotalOperators and TotalOperands are N1 and N2.
+	DistinctOperators int `json:"distinctOperators"`
+	DistinctOperands  int `json:"distinctOperands"`
+	TotalOperators    int `json:"totalOperators"`
+	TotalOperands     int `json:"totalOperands"`
+	// Vocabulary is n1 + n2 and Length is N1 + N2.
+	Vocabulary int `json:"vocabulary"`
+	Length     int `json:"length"`
+	// Volume is Length × log2(Vocabulary).
+	Volume float64 `json:"volume"`
+	// Difficulty is n1/2 × N2/n2, how hard the code is to write or understand.
+	Difficulty float64 `json:"difficulty"`
+	// Effort is Difficulty × Volume.
+	Effort float64 `json:"effort"`
+	// Time is the estimated programming time in seconds, Effort / 18.
+	Time float64 `json:"time"`
+	// Bugs is the estimated number of delivered bugs, Volume / 3000.
+	Bugs float64 `json:"bugs"`
+	// Level is the program level, 1 / Difficulty: how close the code is to its most compact form.
+	Level float64 `json:"level"`
+	// LanguageLevel is Level² × Volume, which Halstead expected to be constant for a language.
+	LanguageLevel float64 `json:"languageLevel"`
+	// IntelligenceContent is Volume / Difficulty, the amount of content of the code regardless of the language.
+	IntelligenceContent float64 `json:"intelligenceContent"`
+}
+
+// CalculateHalsteadVolume computes a simplified Halstead Volume based on operator and operand counts.
+func CalculateHalsteadVolume(src string) float64 {
+	return CalculateHalstead(src, HalsteadClassic).Volume
+}
+
+// CalculateHalsteadVolumeWithProfile computes the Halstead Volume, classifying operators with the given profile.
+func CalculateHalsteadVolumeWithProfile(src string, profile HalsteadProfile) float64 {
+	return CalculateHalstead(src, profile).Volume
+}
+
+// CalculateHalstead computes the Halstead metrics of the source, classifying operators with the given profile.
+func CalculateHalstead(src string, profile HalsteadProfile) HalsteadMetrics {
+	operators, operands := CountHalsteadTokens(src, profile)
+	var h HalsteadMetrics
+	for _, count := range operators {
+		h.TotalOperators += count
+	}
+	for _, count := range operands {
+		h.TotalOperands += count
+	}
+	h.DistinctOperators = len(operators)
+	h.DistinctOperands = len(operands)
+	h.Vocabulary = h.DistinctOperators + h.DistinctOperands
+	h.Length = h.TotalOperators + h.TotalOperands
+	if h.Vocabulary > 0 {
+		h.Volume = float64(h.Length) * math.Log2(float64(h.Vocabulary))
+	}
+	if h.DistinctOperands > 0 {
+		h.Difficulty = float64(h.DistinctOperators) / 2 * float64(h.TotalOperands) / float64(h.DistinctOperands)
+	}
+	h.Effort = h.Difficulty * h.Volume
+	h.Time = h.Effort / 18
+	h.Bugs = h.Volume / 3000
+	if h.Difficulty > 0 {
+		h.Level = 1 / h.Difficulty
+		h.LanguageLevel = h.Level * h.Level * h.Volume
+		h.IntelligenceContent = h.Volume / h.Difficulty
+	}
+	return h
+}
+
+// CountHalsteadTokens counts how often each operator and operand occurs in the source, classifying
+// operators with the given profile. Tokens are keyed by their text, e.g. "+=" or "err".
+func CountHalsteadTokens(src string, profile HalsteadProfile) (operators, operands map[string]int) {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+
+	var tokens []scannedToken
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Comments and semicolons inserted at line ends are not part of the program text.
+		if tok == token.COMMENT || (tok == token.SEMICOLON && lit == "\n") {
+			continue
+		}
+		tokens = append(tokens, scannedToken{tok, lit})
+	}
+
+	operators = make(map[string]int)
+	operands = make(map[string]int)
+	for i, t := range tokens {
+		var next scannedToken
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+		key := t.lit
+		if key == "" {
+			key = t.tok.String()
+		}
+		if profile.isOperator(t, next) {
+			operators[key]++
+		} else if isOperand(t.tok) {
+			operands[key]++
+		}
+	}
+	return operators, operands
+}
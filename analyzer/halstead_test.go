@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+// halsteadSnippet is the fixed input of the profile tests. Its tokens are
+//
+//	x := a + b
+//	if x > 0 { return f ( x ) }
+//
+// classic counts the operators :=, + and > (n1 = 3, N1 = 3) and the operands x (3 times), a, b,
+// 0 and f (n2 = 5, N2 = 7). go-keywords adds the keywords if and return. extended also adds the
+// opening delimiters { and ( and the call of f, which is then no longer an operand.
+const halsteadSnippet = `x := a + b
+if x > 0 {
+	return f(x)
+}
+`
+
+func TestHalsteadVolumeByProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile HalsteadProfile
+		n1, n2  int
+		N1, N2  int
+	}{
+		{name: "empty means classic", profile: "", n1: 3, n2: 5, N1: 3, N2: 7},
+		{name: "classic", profile: HalsteadClassic, n1: 3, n2: 5, N1: 3, N2: 7},
+		{name: "go-keywords", profile: HalsteadGoKeywords, n1: 5, n2: 5, N1: 5, N2: 7},
+		{name: "extended", profile: HalsteadExtended, n1: 8, n2: 4, N1: 8, N2: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CalculateHalstead(halsteadSnippet, tt.profile)
+			if h.DistinctOperators != tt.n1 || h.DistinctOperands != tt.n2 || h.TotalOperators != tt.N1 || h.TotalOperands != tt.N2 {
+				t.Errorf("counts n1=%d n2=%d N1=%d N2=%d, want n1=%d n2=%d N1=%d N2=%d",
+					h.DistinctOperators, h.DistinctOperands, h.TotalOperators, h.TotalOperands, tt.n1, tt.n2, tt.N1, tt.N2)
+			}
+			want := float64(tt.N1+tt.N2) * math.Log2(float64(tt.n1+tt.n2))
+			if got := CalculateHalsteadVolumeWithProfile(halsteadSnippet, tt.profile); math.Abs(got-want) > 1e-9 {
+				t.Errorf("volume %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestHalsteadVolumeGrowsWithProfile(t *testing.T) {
+	classic := CalculateHalsteadVolumeWithProfile(halsteadSnippet, HalsteadClassic)
+	keywords := CalculateHalsteadVolumeWithProfile(halsteadSnippet, HalsteadGoKeywords)
+	extended := CalculateHalsteadVolumeWithProfile(halsteadSnippet, HalsteadExtended)
+	if classic != 30 {
+		t.Errorf("classic volume %v, want 30", classic)
+	}
+	if !(classic < keywords && keywords < extended) {
+		t.Errorf("volumes classic %v, go-keywords %v, extended %v, want each profile above the previous one", classic, keywords, extended)
+	}
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// ImportProfile splits the imports of a file or package by origin.
+type ImportProfile struct {
+	Stdlib int `json:"stdlib"`
+	// Module counts the imports of packages of the same module.
+	Module     int `json:"module"`
+	ThirdParty int `json:"thirdParty"`
+}
+
+// Total returns the number of imports.
+func (p ImportProfile) Total() int {
+	return p.Stdlib + p.Module + p.ThirdParty
+}
+
+// IsStandardImport reports whether an import path belongs to the standard library, whose first
+// path element has no dot, unlike a module path such as github.com/...
+func IsStandardImport(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// ClassifyImports builds the import profile of import paths within the given module. Without a
+// module path, every import outside the standard library is third-party.
+func ClassifyImports(paths []string, modulePath string) ImportProfile {
+	var p ImportProfile
+	for _, path := range paths {
+		switch {
+		case modulePath != "" && (path == modulePath || strings.HasPrefix(path, modulePath+"/")):
+			p.Module++
+		case IsStandardImport(path):
+			p.Stdlib++
+		default:
+			p.ThirdParty++
+		}
+	}
+	return p
+}
+
+// ImportPaths returns the import paths of Go source. The source only needs to parse up to its
+// imports; the imports read before a syntax error are returned.
+func ImportPaths(src []byte) []string {
+	f, _ := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+	if f == nil {
+		return nil
+	}
+	var paths []string
+	for _, spec := range f.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
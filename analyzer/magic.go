@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+// DefaultMagicNumberAllowlist lists the numbers that are common enough not to count as magic numbers.
+var DefaultMagicNumberAllowlist = []float64{-1, 0, 1}
+
+// CountMagicNumbers counts the integer and floating-point literals in a function body outside of
+// const declarations, whose meaning is not named. A negated literal such as -1 counts as one
+// number. Numbers in the allowlist are not counted.
+func CountMagicNumbers(body *ast.BlockStmt, allowlist []float64) int {
+	count := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			return node.Tok != token.CONST
+		case *ast.UnaryExpr:
+			if lit, ok := node.X.(*ast.BasicLit); ok && node.Op == token.SUB {
+				if isMagicNumber(lit, true, allowlist) {
+					count++
+				}
+				return false
+			}
+		case *ast.BasicLit:
+			if isMagicNumber(node, false, allowlist) {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// isMagicNumber reports whether a literal, negated or not, is a number missing from the allowlist
+func isMagicNumber(lit *ast.BasicLit, negated bool, allowlist []float64) bool {
+	if lit.Kind != token.INT && lit.Kind != token.FLOAT {
+		return false
+	}
+	value := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+	if negated {
+		value = constant.UnaryOp(token.SUB, value, 0)
+	}
+	for _, allowed := range allowlist {
+		if constant.Compare(value, token.EQL, constant.MakeFloat64(allowed)) {
+			return false
+		}
+	}
+	return true
+}
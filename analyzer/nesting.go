@@ -0,0 +1,50 @@
+package analyzer
+
+import "go/ast"
+
+// CalculateMaxNesting returns the deepest nesting of if, for, range, switch, type switch and select
+// statements and function literals in a function body. Statements directly in the body are at
+// depth 0. An else if continues its chain at the depth of the first if rather than nesting deeper.
+func CalculateMaxNesting(body *ast.BlockStmt) int {
+	if body == nil {
+		return 0
+	}
+	return nestingDepth(body, 0)
+}
+
+// nestingDepth returns the deepest nesting within the children of node, which are at the given depth
+func nestingDepth(node ast.Node, depth int) int {
+	deepest := depth
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == node || n == nil {
+			return true
+		}
+		switch x := n.(type) {
+		case *ast.IfStmt:
+			deepest = max(deepest, ifNesting(x, depth))
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+			deepest = max(deepest, nestingDepth(x, depth+1))
+		default:
+			return true
+		}
+		return false
+	})
+	return deepest
+}
+
+// ifNesting returns the deepest nesting of an if statement at the given depth and its else branches
+func ifNesting(stmt *ast.IfStmt, depth int) int {
+	deepest := nestingDepth(stmt.Body, depth+1)
+	for _, part := range []ast.Node{stmt.Init, stmt.Cond} {
+		if part != nil {
+			deepest = max(deepest, nestingDepth(part, depth+1))
+		}
+	}
+	switch e := stmt.Else.(type) {
+	case *ast.IfStmt:
+		deepest = max(deepest, ifNesting(e, depth))
+	case *ast.BlockStmt:
+		deepest = max(deepest, nestingDepth(e, depth+1))
+	}
+	return deepest
+}
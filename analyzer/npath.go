@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+	"math/big"
+)
+
+// CalculateNPath returns the NPath complexity of a function body: the number of acyclic execution
+// paths through it. Sequential statements multiply their path counts, while branches add them, so
+// the count grows exponentially with consecutive decisions. Each && and || in a condition adds a
+// path. Function literals count as plain statements, and loops count as either skipping or
+// running their body once. The count is computed with arbitrary precision, as it easily exceeds
+// the range of machine integers.
+func CalculateNPath(body *ast.BlockStmt) *big.Int {
+	if body == nil {
+		return big.NewInt(1)
+	}
+	return npathStmts(body.List)
+}
+
+// NPathInt64 returns an NPath count as an int64, capped at math.MaxInt64.
+func NPathInt64(npath *big.Int) int64 {
+	if !npath.IsInt64() {
+		return math.MaxInt64
+	}
+	return npath.Int64()
+}
+
+// npathStmts returns the product of the path counts of a statement sequence
+func npathStmts(stmts []ast.Stmt) *big.Int {
+	paths := big.NewInt(1)
+	for _, stmt := range stmts {
+		paths.Mul(paths, npathStmt(stmt))
+	}
+	return paths
+}
+
+// npathStmt returns the number of acyclic paths through a single statement
+func npathStmt(stmt ast.Stmt) *big.Int {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return npathStmts(s.List)
+	case *ast.LabeledStmt:
+		return npathStmt(s.Stmt)
+	case *ast.IfStmt:
+		paths := npathStmts(s.Body.List)
+		if s.Else != nil {
+			paths.Add(paths, npathStmt(s.Else))
+		} else {
+			paths.Add(paths, big.NewInt(1))
+		}
+		return paths.Add(paths, big.NewInt(int64(countBoolOps(s.Cond))))
+	case *ast.ForStmt:
+		paths := npathStmts(s.Body.List)
+		paths.Add(paths, big.NewInt(1))
+		return paths.Add(paths, big.NewInt(int64(countBoolOps(s.Cond))))
+	case *ast.RangeStmt:
+		paths := npathStmts(s.Body.List)
+		return paths.Add(paths, big.NewInt(1))
+	case *ast.SwitchStmt:
+		paths := npathClauses(s.Body)
+		return paths.Add(paths, big.NewInt(int64(countBoolOps(s.Tag))))
+	case *ast.TypeSwitchStmt:
+		return npathClauses(s.Body)
+	case *ast.SelectStmt:
+		return npathClauses(s.Body)
+	}
+	return big.NewInt(1)
+}
+
+// npathClauses returns the sum of the path counts of the clauses of a switch or select statement,
+// plus one path for falling through when there is no default clause
+func npathClauses(body *ast.BlockStmt) *big.Int {
+	paths := new(big.Int)
+	hasDefault := false
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			hasDefault = hasDefault || c.List == nil
+			for _, expr := range c.List {
+				paths.Add(paths, big.NewInt(int64(countBoolOps(expr))))
+			}
+			paths.Add(paths, npathStmts(c.Body))
+		case *ast.CommClause:
+			hasDefault = hasDefault || c.Comm == nil
+			paths.Add(paths, npathStmts(c.Body))
+		}
+	}
+	if !hasDefault {
+		paths.Add(paths, big.NewInt(1))
+	}
+	return paths
+}
+
+// countBoolOps counts the && and || operators in an expression, outside of function literals
+func countBoolOps(expr ast.Expr) int {
+	if expr == nil {
+		return 0
+	}
+	count := 0
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
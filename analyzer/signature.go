@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// CountFields counts the entries of a parameter or result list, counting each name of a
+// grouped field such as (a, b int) separately.
+func CountFields(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
+}
+
+// ReturnsError reports whether any of the function's results is of type error.
+func ReturnsError(fnType *ast.FuncType) bool {
+	if fnType.Results == nil {
+		return false
+	}
+	for _, field := range fnType.Results.List {
+		if ident, ok := field.Type.(*ast.Ident); ok && ident.Name == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// FindPanicCalls returns the line numbers of the panic calls in a function body.
+// Calls inside function literals are skipped, since those have their own signatures.
+func FindPanicCalls(fset *token.FileSet, body *ast.BlockStmt) []int {
+	var lines []int
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				lines = append(lines, fset.Position(node.Pos()).Line)
+			}
+		}
+		return true
+	})
+	return lines
+}
+
+// CountRecovers counts the recover calls in a function body, including those in function literals,
+// where they usually are, as recover only stops a panic when called by a deferred function.
+func CountRecovers(body *ast.BlockStmt) int {
+	count := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "recover" {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// CountExits counts the return, break, continue and goto statements and the panic calls in a
+// function body. Function literals are skipped, since their exits leave only the literal.
+func CountExits(body *ast.BlockStmt) int {
+	count := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt, *ast.BranchStmt:
+			// fallthrough only moves to the next case, so it is not an exit.
+			if branch, ok := node.(*ast.BranchStmt); ok && branch.Tok == token.FALLTHROUGH {
+				break
+			}
+			count++
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
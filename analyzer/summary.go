@@ -0,0 +1,81 @@
+package analyzer
+
+import "sort"
+
+// Summary holds aggregate metrics over a set of analyzed functions.
+type Summary struct {
+	Functions       int
+	TotalCyclomatic int
+	TotalLOC        int
+	AverageMI       float64
+}
+
+// Summarize computes aggregate metrics for the given function results.
+func Summarize(results []MethodResult) Summary {
+	var s Summary
+	totalMI := 0.0
+	for _, res := range results {
+		s.Functions++
+		s.TotalCyclomatic += res.Cyclomatic
+		s.TotalLOC += res.LOC
+		totalMI += res.MaintainabilityIndex
+	}
+	if s.Functions > 0 {
+		s.AverageMI = totalMI / float64(s.Functions)
+	}
+	return s
+}
+
+// Variance returns the population variance of the values.
+func Variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values))
+}
+
+// Median returns the middle value of the values, or the mean of the two middle values for an
+// even count. The values are not modified.
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// FileDensity holds the comment density of a single file together with its size in lines.
+type FileDensity struct {
+	Path           string
+	Lines          int
+	CommentDensity float64
+}
+
+// WeightedCommentDensity returns the average comment density of the files weighted by their
+// line count, so that large files count proportionally more than small ones.
+func WeightedCommentDensity(files []FileDensity) float64 {
+	totalLines := 0
+	weighted := 0.0
+	for _, f := range files {
+		totalLines += f.Lines
+		weighted += f.CommentDensity * float64(f.Lines)
+	}
+	if totalLines == 0 {
+		return 0
+	}
+	return weighted / float64(totalLines)
+}
@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// Type kinds reported in TypeResult.
+const (
+	KindStruct    = "struct"
+	KindInterface = "interface"
+)
+
+// TypeResult holds the metrics of a struct or interface type. The methods of a struct type are
+// those declared with it in the same file, while those of an interface are its method elements.
+type TypeResult struct {
+	Name    string  `json:"name"`
+	Kind    string  `json:"kind"`
+	Line    int     `json:"line"`
+	Fields  int     `json:"fields"`
+	Methods int     `json:"methods"`
+	LCOM    float64 `json:"lcom"`
+	WMC     int     `json:"wmc"`
+}
+
+// AnalyzeTypes analyzes the struct and interface types of Go source held in memory.
+func AnalyzeTypes(name string, src []byte, opts Options) ([]TypeResult, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, opts.ParserMode)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []TypeResult
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			result := TypeResult{Name: typeSpec.Name.Name, Line: fset.Position(typeSpec.Pos()).Line}
+			switch t := typeSpec.Type.(type) {
+			case *ast.StructType:
+				methods := typeMethods(f, typeSpec.Name.Name)
+				fields := structFieldNames(t)
+				result.Kind = KindStruct
+				result.Fields = len(fields)
+				result.Methods = len(methods)
+				result.LCOM = CalculateLCOM(methods, fields)
+				result.WMC = CalculateWMC(methods)
+			case *ast.InterfaceType:
+				result.Kind = KindInterface
+				result.Methods = CountInterfaceMethods(t)
+			default:
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// typeMethods returns the methods with a body declared in the file for the named receiver type
+func typeMethods(f *ast.File, typeName string) []*ast.FuncDecl {
+	var methods []*ast.FuncDecl
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil && ReceiverTypeName(fn) == typeName {
+			methods = append(methods, fn)
+		}
+	}
+	return methods
+}
+
+// CountInterfaceMethods counts the methods declared in an interface. Embedded interfaces and type
+// constraint elements are not counted.
+func CountInterfaceMethods(iface *ast.InterfaceType) int {
+	count := 0
+	for _, field := range iface.Methods.List {
+		count += len(field.Names)
+	}
+	return count
+}
+
+// structFieldNames returns the names of the fields of a struct, naming embedded fields after their type
+func structFieldNames(structType *ast.StructType) []string {
+	var names []string
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			if name := embeddedFieldName(field.Type); name != "" {
+				names = append(names, name)
+			}
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// embeddedFieldName returns the field name of an embedded type such as T, *T, pkg.T or T[int]
+func embeddedFieldName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(x.X)
+	case *ast.IndexExpr:
+		return embeddedFieldName(x.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(x.X)
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.Ident:
+		return x.Name
+	}
+	return ""
+}
+
+// CalculateLCOM returns the Henderson-Sellers lack of cohesion of methods of a type, based on
+// which of its fields each method accesses through its receiver:
+//
+//	LCOM = (methods - mean number of methods accessing a field) / (methods - 1)
+//
+// It is 0 when every method accesses every field and reaches 1 when each field is used by a
+// single method; values above 1 mean that some methods access no field at all. Types with fewer
+// than two methods or without fields have an LCOM of 0.
+func CalculateLCOM(methods []*ast.FuncDecl, fields []string) float64 {
+	m := len(methods)
+	if m < 2 || len(fields) == 0 {
+		return 0
+	}
+	isField := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		isField[field] = true
+	}
+	accesses := 0
+	for _, method := range methods {
+		accesses += len(accessedFields(method, isField))
+	}
+	meanAccess := float64(accesses) / float64(len(fields))
+	return (float64(m) - meanAccess) / (float64(m) - 1)
+}
+
+// CalculateWMC returns the weighted methods per class of a type: the sum of the cyclomatic
+// complexity of its methods.
+func CalculateWMC(methods []*ast.FuncDecl) int {
+	wmc := 0
+	for _, method := range methods {
+		wmc += CalculateCyclomaticComplexity(method.Body)
+	}
+	return wmc
+}
+
+// accessedFields returns the fields a method accesses as selectors on its receiver
+func accessedFields(method *ast.FuncDecl, isField map[string]bool) map[string]bool {
+	accessed := make(map[string]bool)
+	names := method.Recv.List[0].Names
+	if len(names) == 0 || names[0].Name == "_" {
+		return accessed
+	}
+	receiver := names[0].Name
+	ast.Inspect(method.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == receiver && isField[sel.Sel.Name] {
+				accessed[sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return accessed
+}
@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// UnusedExport is an exported function or type that nothing refers to within the analyzed packages.
+type UnusedExport struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	// Kind is "func" or "type".
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Complexity is the cyclomatic complexity removing it would remove: that of the function, or
+	// the sum over the methods of the type.
+	Complexity int `json:"complexity"`
+}
+
+// CollectReferences marks the objects that the files of a type-checked package refer to. The
+// receiver of a method does not count as a reference to its type, or every type with methods
+// would be referenced. Generic functions and types are marked rather than their instantiations.
+func CollectReferences(files []*ast.File, info *types.Info, referenced map[types.Object]bool) {
+	receivers := make(map[*ast.Ident]bool)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				if ident := receiverTypeIdent(fn); ident != nil {
+					receivers[ident] = true
+				}
+			}
+		}
+	}
+	for ident, obj := range info.Uses {
+		if receivers[ident] {
+			continue
+		}
+		switch o := obj.(type) {
+		case *types.Func:
+			obj = o.Origin()
+		case *types.TypeName:
+			if named, ok := o.Type().(*types.Named); ok {
+				obj = named.Origin().Obj()
+			}
+		}
+		referenced[obj] = true
+	}
+}
+
+// FindUnusedExports returns the exported top-level functions and types of a type-checked package
+// that are not referenced, in source order. Methods are not reported, since a method may be needed
+// to implement an interface without ever being called by name.
+func FindUnusedExports(fset *token.FileSet, pkgPath string, files []*ast.File, info *types.Info, referenced map[types.Object]bool) []UnusedExport {
+	methods := make(map[string][]*ast.FuncDecl)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Body != nil {
+				methods[ReceiverTypeName(fn)] = append(methods[ReceiverTypeName(fn)], fn)
+			}
+		}
+	}
+	var unused []UnusedExport
+	report := func(name *ast.Ident, kind string, complexity int) {
+		if !name.IsExported() || info.Defs[name] == nil || referenced[info.Defs[name]] {
+			return
+		}
+		pos := fset.Position(name.Pos())
+		unused = append(unused, UnusedExport{Package: pkgPath, Name: name.Name, Kind: kind, File: pos.Filename, Line: pos.Line, Complexity: complexity})
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Body != nil {
+					report(decl.Name, "func", CalculateCyclomaticComplexity(decl.Body))
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					name := spec.(*ast.TypeSpec).Name
+					report(name, "type", CalculateWMC(methods[name.Name]))
+				}
+			}
+		}
+	}
+	return unused
+}
@@ -0,0 +1,444 @@
+package cli
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"go/parser"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// analyzeOptions holds the options accepted by the analyze command
+type analyzeOptions struct {
+	filePath     string
+	filePatterns []string
+	dirPath      string
+	recursive    bool
+	packages     []string
+	stdin        bool
+	repo         string
+	archive      string
+	diffBase     string
+	excludes     []string
+	// changedLines restricts the results to functions overlapping these ranges, keyed by file
+	changedLines map[string][]lineRange
+	budgetsPath  string
+	exportedOnly bool
+	verbose      bool
+	quiet        bool
+	started      time.Time
+	lint         bool
+	bySeverity   bool
+	sortBy       string
+	top          int
+	minCC        int
+	minLOC       int
+	maxMI        *float64
+	onlyBreach   bool
+	thresholds   []*float64
+	format       string
+	output       string
+	markdownAll  bool
+	parserMode   parser.Mode
+	failOn       []string
+	cache        bool
+	match        *regexp.Regexp
+	buildTags    []string
+	// previous holds the results of the previous watch run, keyed by qualified name, for trend annotations
+	previous map[string]analyzer.MethodResult
+}
+
+// validFormats lists the output formats accepted by the analyze command
+var validFormats = append([]string{"text", "json", "ndjson"}, collectedFormats...)
+
+// multiFileFormats lists the output formats that support analyzing more than one file
+var multiFileFormats = append([]string{"text", "ndjson"}, collectedFormats...)
+
+// validFailOn lists the values accepted by --fail-on
+var validFailOn = []string{"results", "panics", "medium", "high"}
+
+// analyzeFlags defines the options of the analyze command, which are stored in opts
+func analyzeFlags(opts *analyzeOptions) *flagSet {
+	flags := newFlagSet("analyze",
+		"-f <file|glob>... [options]",
+		"-d <directory> [-r] [options]",
+		"-p <package pattern> [options]",
+		"--stdin | - [options]",
+		"--repo <url[@ref]> | --archive <file> | --diff <base-ref> [options]")
+	flags.listVar(&opts.filePatterns, "file", "f", "", "analyze the Go `file`s or globs (** matches any directories); more may follow as arguments")
+	flags.stringVar(&opts.dirPath, "dir", "d", "analyze the Go files in the `directory`; a trailing /... also analyzes its subdirectories")
+	flags.boolVar(&opts.recursive, "recursive", "r", "include the subdirectories of --dir")
+	flags.listVar(&opts.packages, "package", "p", "", "analyze the packages matching the `pattern`, e.g. ./...")
+	flags.boolVar(&opts.stdin, "stdin", "", "analyze the source read from standard input")
+	flags.stringVar(&opts.repo, "repo", "", "clone the repository at the `url[@ref]` and analyze it")
+	flags.stringVar(&opts.archive, "archive", "", "analyze the Go files in the .zip or .tar.gz `file`")
+	flags.stringVar(&opts.diffBase, "diff", "", "analyze the functions changed since the git `base-ref`")
+	flags.listVar(&opts.excludes, "exclude", "", "", "skip the files matching the `pattern`")
+	flags.stringVar(&opts.budgetsPath, "budgets", "", "check the complexity budgets in the `file`")
+	flags.boolVar(&opts.exportedOnly, "exported-only", "", "report only exported functions")
+	flags.boolVar(&opts.verbose, "verbose", "v", "show every metric of each function, and the timing, skipped files and type errors on stderr")
+	flags.boolVar(&opts.quiet, "quiet", "q", "print only the threshold violations and the verdict")
+	flags.boolVar(&opts.lint, "lint", "", "suggest refactorings")
+	flags.boolVar(&opts.bySeverity, "group-by-severity", "", "group the functions by severity")
+	flags.intVar(&opts.minCC, "min-cc", "", "report only the functions with a cyclomatic complexity of at least `n`")
+	flags.intVar(&opts.minLOC, "min-loc", "", "report only the functions with at least `n` lines of code")
+	flags.optionalFloatVar(&opts.maxMI, "max-mi", "", "report only the functions with a maintainability index of at most `value`")
+	flags.boolVar(&opts.onlyBreach, "only-violations", "", "report only the functions that reach a medium or high threshold")
+	flags.stringVar(&opts.sortBy, "sort", "", "list the functions worst first by the `metric`: "+strings.Join(sortKeyNames(), ", "))
+	flags.intVar(&opts.top, "top", "", "list only the `n` worst functions, by cyclomatic complexity unless --sort is given")
+	flags.stringVar(&opts.format, "format", "", "write the report in the `format`: "+strings.Join(validFormats, ", "))
+	flags.stringVar(&opts.output, "output", "o", "write the report to the `file` instead of standard output, creating its directory")
+	flags.boolVar(&opts.markdownAll, "markdown-all", "", "list every function in the markdown report, not only the flagged ones")
+	flags.define(parserModeValue{&opts.parserMode, parser.SkipObjectResolution}, "skip-resolution", "", "parse without resolving identifiers")
+	flags.define(parserModeValue{&opts.parserMode, parser.DeclarationErrors}, "strict-parse", "", "report declaration errors")
+	flags.listVar(&opts.failOn, "fail-on", "", ",", "exit with an error when a function breaches the `gates`: "+strings.Join(validFailOn, ", "))
+	flags.boolVar(&opts.cache, "cache", "", "reuse the results of unchanged files")
+	flags.define(regexpValue{&opts.match}, "match", "", "report only the functions whose name matches the `regexp`")
+	flags.listVar(&opts.buildTags, "build-tags", "", ",", "skip the files whose build constraint does not match the comma-separated `tags`")
+	defineThresholdFlags(flags, opts)
+	return flags
+}
+
+// applyAnalyzeArgs applies the positional arguments of the analyze command to its options:
+// "-" reads standard input and other arguments are more files for -f
+func applyAnalyzeArgs(opts *analyzeOptions, positional []string) error {
+	for _, arg := range positional {
+		switch {
+		case arg == "-":
+			opts.stdin = true
+		case opts.filePatterns != nil:
+			opts.filePatterns = append(opts.filePatterns, arg)
+		default:
+			return fmt.Errorf("unexpected argument '%s'", arg)
+		}
+	}
+	return validateAnalyzeOptions(opts)
+}
+
+// validateAnalyzeOptions checks that the options of the analyze command fit together
+func validateAnalyzeOptions(opts *analyzeOptions) error {
+	opts.dirPath, opts.recursive = recursiveDir(opts.dirPath, opts.recursive)
+	targets := 0
+	for _, set := range []bool{opts.filePatterns != nil, opts.dirPath != "", opts.packages != nil, opts.stdin, opts.repo != "", opts.archive != "", opts.diffBase != ""} {
+		if set {
+			targets++
+		}
+	}
+	if targets == 0 {
+		return fmt.Errorf("no file specified")
+	}
+	if targets > 1 {
+		return fmt.Errorf("-f, -d, -p, --stdin, --repo, --archive and --diff cannot be used together")
+	}
+	if (opts.dirPath != "" || opts.packages != nil || opts.repo != "" || opts.archive != "" || opts.diffBase != "") && !containsString(multiFileFormats, opts.format) {
+		return fmt.Errorf("analysis of multiple files supports only these formats: %s", strings.Join(multiFileFormats, ", "))
+	}
+	if !containsString(validFormats, opts.format) {
+		return fmt.Errorf("unknown format '%s'. Valid formats: %s", opts.format, strings.Join(validFormats, ", "))
+	}
+	if _, ok := findSortKey(opts.sortBy); opts.sortBy != "" && !ok {
+		return fmt.Errorf("unknown --sort metric '%s'. Valid metrics: %s", opts.sortBy, strings.Join(sortKeyNames(), ", "))
+	}
+	if opts.quiet && opts.verbose {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+	if opts.top < 0 || opts.minCC < 0 || opts.minLOC < 0 {
+		return fmt.Errorf("--top, --min-cc and --min-loc must not be negative")
+	}
+	for _, value := range opts.failOn {
+		if !containsString(validFailOn, value) {
+			return fmt.Errorf("unknown --fail-on value '%s'. Valid values: %s", value, strings.Join(validFailOn, ", "))
+		}
+	}
+	return nil
+}
+
+// handleAnalyzeCommand processes the analyze command
+func handleAnalyzeCommand(args []string) {
+	opts := analyzeOptions{format: "text", started: time.Now()}
+	flags := analyzeFlags(&opts)
+	positional := parseCommandArgs(flags, args[1:])
+	if err := flags.applyEnvironment(); err != nil {
+		failUsage(flags, err)
+	}
+	if err := applyAnalyzeArgs(&opts, positional); err != nil {
+		failUsage(flags, err)
+	}
+
+	cfg, budgets := loadAnalyzeSettings(flags, opts)
+	if opts.output != "" {
+		if err := redirectOutput(opts.output); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error creating output file: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	}
+	analyzeInput(cfg, opts, budgets)
+}
+
+// loadAnalyzeSettings loads the configuration of the analyzed path with the threshold options
+// applied, and the budgets file of --budgets
+func loadAnalyzeSettings(flags *flagSet, opts analyzeOptions) (*Config, Budgets) {
+	locateConfig(analyzedPath(opts))
+	applyTheme()
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if err := applyThresholdFlags(cfg, opts); err != nil {
+		failUsage(flags, err)
+	}
+
+	var budgets Budgets
+	if opts.budgetsPath != "" {
+		budgets, err = LoadBudgets(opts.budgetsPath)
+		if err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error loading budgets: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	}
+	return cfg, budgets
+}
+
+// analyzeInput analyzes the input the options select: a directory, packages, a repository, an
+// archive, the changes since a git ref, files, or standard input
+func analyzeInput(cfg *Config, opts analyzeOptions, budgets Budgets) {
+	switch {
+	case opts.dirPath != "":
+		analyzeDirectory(opts.dirPath, cfg, opts, budgets)
+	case opts.packages != nil:
+		analyzePackages(opts.packages, cfg, opts, budgets)
+	case opts.repo != "":
+		analyzeRepo(opts.repo, cfg, opts, budgets)
+	case opts.archive != "":
+		analyzeArchive(opts.archive, cfg, opts, budgets)
+	case opts.diffBase != "":
+		analyzeDiff(opts.diffBase, cfg, opts, budgets)
+	case opts.filePatterns != nil:
+		analyzeFiles(opts.filePatterns, cfg, opts, budgets)
+	default:
+		analyzeFile(cfg, opts, budgets)
+	}
+}
+
+// analyzeFiles analyzes the files matching the patterns, as a run over several files
+// unless the patterns name a single file
+func analyzeFiles(patterns []string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	files, err := expandFilePatterns(patterns)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if len(files) > 1 {
+		if !containsString(multiFileFormats, opts.format) {
+			fmt.Fprintln(stderr, ColorRed+"Error: analysis of multiple files supports only these formats: "+strings.Join(multiFileFormats, ", ")+ColorReset)
+			exit(exitError)
+		}
+		analyzeGroups([]fileGroup{{files: files}}, ".", cfg, opts, budgets)
+		return
+	}
+	opts.filePath = files[0]
+	analyzeFile(cfg, opts, budgets)
+}
+
+// fileAnalysis holds the findings of a single analyzed file, which its report presents
+type fileAnalysis struct {
+	path            string
+	src             []byte
+	buildConstraint constraint.Expr
+	results         []analyzer.MethodResult
+	commentDensity  float64
+	excluded        int
+	findings        []LintFinding
+	violations      []BudgetViolation
+	failed          bool
+	gate            []gateCondition
+}
+
+// analyzeFile analyzes the file of the options, or standard input, and writes its report
+func analyzeFile(cfg *Config, opts analyzeOptions, budgets Budgets) {
+	absPath, src, err := readAnalyzeInput(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error reading input: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if opts.stdin {
+		// Reports name standard input as if it were the analyzed file.
+		opts.filePath = stdinName
+	} else {
+		cfg = cfg.ForFile(absPath)
+	}
+
+	buildConstraint, err := analyzer.BuildConstraint(src)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error reading build constraint: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if opts.buildTags != nil && !analyzer.MatchesTags(buildConstraint, opts.buildTags) {
+		fmt.Fprintln(stdout, ColorYellow+fmt.Sprintf("Skipping %s: build constraint '%s' does not match tags %s.",
+			opts.filePath, buildConstraint, strings.Join(opts.buildTags, ","))+ColorReset)
+		return
+	}
+
+	results, commentDensity, excluded, err := analyzeSource(absPath, src, cfg, opts)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error during analysis: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	logElapsed(opts, 1)
+
+	file := fileAnalysis{path: absPath, src: src, buildConstraint: buildConstraint, results: results, commentDensity: commentDensity, excluded: excluded}
+	if opts.lint {
+		file.findings = append(LintResults(results, cfg), LintFile(src, cfg)...)
+	}
+	if opts.budgetsPath != "" {
+		file.violations = CheckBudgets(absPath, results, budgets, cfg)
+	}
+	file.failed = failOnViolated(results, cfg, opts)
+	file.gate = EvaluateQualityGate([]fileResults{{file: absPath, results: results}}, cfg)
+
+	writeFileAnalysis(file, cfg, opts)
+	if len(file.violations) > 0 || file.failed || !gatePassed(file.gate) {
+		exit(exitViolations)
+	}
+}
+
+// writeFileAnalysis writes the report of a single file in the format of the options. The quality
+// gate follows the text report, and goes to stderr with the other formats so that it does not
+// mix with their output.
+func writeFileAnalysis(file fileAnalysis, cfg *Config, opts analyzeOptions) {
+	switch opts.format {
+	case "text":
+		if opts.quiet {
+			printQuietReport([]fileResults{{file: file.path, results: file.results}}, file.violations, file.failed, file.gate, cfg, opts)
+			return
+		}
+		printFileAnalysis(file, cfg, opts)
+		printQualityGate(stdout, file.gate)
+		printFooter()
+		return
+	case "json":
+		if err := writeJSON(newFileReport(file, cfg, opts)); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error writing report: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	case "ndjson":
+		if err := writeFileNDJSON(file, cfg, opts); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error writing results: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	default:
+		if err := writeFileReports([]fileResults{{file: opts.filePath, results: file.results}}, cfg, opts); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error writing report: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	}
+	printQualityGate(stderr, file.gate)
+}
+
+// newFileReport returns the JSON report of a single file
+func newFileReport(file fileAnalysis, cfg *Config, opts analyzeOptions) Report {
+	report := NewReport(opts.filePath, file.results, file.commentDensity, cfg)
+	if file.buildConstraint != nil {
+		report.BuildConstraint = file.buildConstraint.String()
+	}
+	report.Types = analyzeTypes(file.path, file.src, cfg, opts)
+	report.Imports = fileImportProfile(file.path, file.src)
+	report.DebtMarkers = analyzer.FindDebtMarkers(file.src, cfg.DebtMarkers)
+	report.LintFindings = file.findings
+	report.BudgetViolations = file.violations
+	report.Summary = NewRunSummary([]fileResults{{file: file.path, results: file.results}}, cfg, opts)
+	return report
+}
+
+// writeFileNDJSON writes the results of a single file as NDJSON, followed by the run summary
+func writeFileNDJSON(file fileAnalysis, cfg *Config, opts analyzeOptions) error {
+	if err := writeNDJSON(file.path, file.results); err != nil {
+		return err
+	}
+	return writeSummaryRecord(NewRunSummary([]fileResults{{file: file.path, results: file.results}}, cfg, opts))
+}
+
+// printFileAnalysis prints the text report of a single file, closed by the run summary
+func printFileAnalysis(file fileAnalysis, cfg *Config, opts analyzeOptions) {
+	printHeader()
+	fmt.Fprintln(stdout, fileHeading(opts.filePath, file.results, cfg))
+	if file.buildConstraint != nil {
+		fmt.Fprintln(stdout, Italic+ColorYellow+"Build constraint: "+file.buildConstraint.String()+ItalicReset+ColorReset)
+	}
+	printImportProfile(fileImportProfile(file.path, file.src), cfg)
+	printFileResults(file.results, file.commentDensity, file.excluded, cfg, opts)
+	printTypeResults(analyzeTypes(file.path, file.src, cfg, opts), cfg)
+	printDebtMarkers(analyzer.FindDebtMarkers(file.src, cfg.DebtMarkers))
+	if opts.lint {
+		printLintFindings(file.findings)
+	}
+	if opts.budgetsPath != "" {
+		printBudgetViolations(file.violations)
+	}
+	if file.failed {
+		fmt.Fprintln(stdout, ColorRed+"Failed: a function breaches a threshold requested with --fail-on ("+strings.Join(opts.failOn, ", ")+")."+ColorReset)
+	}
+	// The run summary and the footer close the report, after every section of the file.
+	fmt.Fprintln(stdout)
+	printRunSummary(NewRunSummary([]fileResults{{file: opts.filePath, results: file.results}}, cfg, opts))
+}
+
+// analyzedPath returns the path whose project configuration applies to the run: the directory, or
+// the first file or the directory of the first glob. Other runs use the current directory.
+func analyzedPath(opts analyzeOptions) string {
+	switch {
+	case opts.dirPath != "":
+		return opts.dirPath
+	case len(opts.filePatterns) > 0:
+		pattern := opts.filePatterns[0]
+		if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+			// The directory of the pattern up to its first glob character, e.g. internal for internal/**/*.go
+			return filepath.Dir(pattern[:i] + "x")
+		}
+		return pattern
+	}
+	return "."
+}
+
+// stdinName is the file name reported for source read from standard input
+const stdinName = "<stdin>"
+
+// readAnalyzeInput returns the absolute path and content of the file to analyze,
+// or the content of standard input when --stdin is set
+func readAnalyzeInput(opts analyzeOptions) (string, []byte, error) {
+	if opts.stdin {
+		src, err := io.ReadAll(os.Stdin)
+		return stdinName, src, err
+	}
+	absPath, err := filepath.Abs(opts.filePath)
+	if err != nil {
+		return "", nil, err
+	}
+	src, err := os.ReadFile(absPath)
+	return absPath, src, err
+}
+
+// failOnViolated reports whether any function breaches a gate requested with --fail-on
+func failOnViolated(results []analyzer.MethodResult, cfg *Config, opts analyzeOptions) bool {
+	for _, res := range results {
+		if containsString(opts.failOn, "results") && GetColorForResults(res.Results, cfg) == ColorRed {
+			return true
+		}
+		// Panicking is a legitimate way for a program to stop, but library code should return errors.
+		if containsString(opts.failOn, "panics") && res.Panics > 0 && res.Package != "main" {
+			return true
+		}
+		severity := GetSeverity(res, cfg)
+		if containsString(opts.failOn, "medium") && severity >= SeverityYellow || containsString(opts.failOn, "high") && severity == SeverityRed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// openArchive returns a read-only file system over a .zip, .tar, .tar.gz or .tgz archive
+// and a function that releases it
+func openArchive(archivePath string) (fs.FS, func() error, error) {
+	switch {
+	case strings.HasSuffix(archivePath, ".zip"):
+		r, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, r.Close, nil
+	case strings.HasSuffix(archivePath, ".tar"), strings.HasSuffix(archivePath, ".tar.gz"), strings.HasSuffix(archivePath, ".tgz"):
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		var r io.Reader = f
+		if !strings.HasSuffix(archivePath, ".tar") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return nil, nil, err
+			}
+			defer gz.Close()
+			r = gz
+		}
+		fsys, err := readTar(r)
+		return fsys, func() error { return nil }, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported archive %s: expected .zip, .tar, .tar.gz or .tgz", archivePath)
+	}
+}
+
+// readTar loads the regular files of a tar stream into an in-memory file system
+func readTar(r io.Reader) (fstest.MapFS, error) {
+	fsys := fstest.MapFS{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return fsys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		fsys[path.Clean(strings.TrimPrefix(header.Name, "/"))] = &fstest.MapFile{Data: data, Mode: fs.FileMode(header.Mode), ModTime: header.ModTime}
+	}
+}
+
+// analyzeArchive analyzes every Go file in an archive without unpacking it to disk
+func analyzeArchive(archivePath string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	fsys, closeArchive, err := openArchive(archivePath)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error opening archive: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	exitHooks = append(exitHooks, func() { _ = closeArchive() })
+
+	var files []string
+	err = fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && strings.HasSuffix(name, ".go") {
+			files = append(files, name)
+		}
+		return err
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error reading archive: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	analyzeGroups([]fileGroup{{files: files, fsys: fsys}}, ".", cfg, opts, budgets)
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// Budgets maps "file.go:FunctionName" keys to the maximum cyclomatic complexity allowed for that function.
+// Methods are keyed by their qualified name, e.g. "file.go:Type.Method".
+type Budgets map[string]int
+
+// BudgetViolation describes a function whose cyclomatic complexity exceeds its budget.
+type BudgetViolation struct {
+	Key        string `json:"key"`
+	Cyclomatic int    `json:"cyclomatic"`
+	Budget     int    `json:"budget"`
+}
+
+// LoadBudgets reads a complexity budgets manifest from the given path.
+func LoadBudgets(path string) (Budgets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var budgets Budgets
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return nil, fmt.Errorf("invalid budgets file %s: %v", path, err)
+	}
+	return budgets, nil
+}
+
+// budgetKeys returns the candidate manifest keys for a function, most specific first.
+// Files are matched by their path relative to the working directory, then by their base name.
+func budgetKeys(filePath string, res analyzer.MethodResult) []string {
+	name := res.QualifiedName()
+	base := filepath.Base(filePath)
+	keys := []string{}
+	if rel, err := relToWorkingDir(filePath); err == nil && filepath.ToSlash(rel) != base {
+		keys = append(keys, filepath.ToSlash(rel)+":"+name)
+	}
+	return append(keys, base+":"+name)
+}
+
+// CheckBudgets compares each function against its budget. A function may reach its budget in the
+// manifest but not exceed it. Functions without an entry are held to the global high cyclomatic
+// threshold like everywhere else: reaching it is a violation, and their budget is reported as the
+// highest complexity below it.
+func CheckBudgets(filePath string, results []analyzer.MethodResult, budgets Budgets, cfg *Config) []BudgetViolation {
+	var violations []BudgetViolation
+	for _, res := range results {
+		keys := budgetKeys(filePath, res)
+		key := keys[len(keys)-1]
+		budget := int(math.Ceil(cfg.Cyclomatic.High)) - 1
+		exceeded := GetColorForCyclomatic(res.Cyclomatic, cfg) == ColorRed
+		for _, k := range keys {
+			if b, ok := budgets[k]; ok {
+				key, budget = k, b
+				exceeded = res.Cyclomatic > b
+				break
+			}
+		}
+		if exceeded {
+			violations = append(violations, BudgetViolation{Key: key, Cyclomatic: res.Cyclomatic, Budget: budget})
+		}
+	}
+	return violations
+}
+
+// printBudgetViolations prints the budget check summary and reports whether all budgets were met.
+func printBudgetViolations(violations []BudgetViolation) bool {
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"Complexity Budgets:"+ColorReset)
+	if len(violations) == 0 {
+		fmt.Fprintln(stdout, ColorGreen+"All functions are within their complexity budgets."+ColorReset)
+		return true
+	}
+	for _, v := range violations {
+		fmt.Fprintf(stdout, "  - %s%s%s: cyclomatic %d exceeds budget %d by %s%d%s\n",
+			ColorCyan, v.Key, ColorReset, v.Cyclomatic, v.Budget, ColorRed, v.Cyclomatic-v.Budget, ColorReset)
+	}
+	fmt.Fprintln(stdout, ColorRed+fmt.Sprintf("%d function(s) exceeded their complexity budget.", len(violations))+ColorReset)
+	return false
+}
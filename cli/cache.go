@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// The result cache has two layers:
+//
+//   - Raw metrics (cyclomatic complexity, Halstead volume, LOC, MI, ...) are expensive to compute and
+//     are cached on disk. An entry is keyed by the file content, the zeds version and the analyzer
+//     options that change the numbers (such as the Halstead profile, MI variant and tab width), so editing thresholds never invalidates it.
+//   - Derived classifications (colors, severities, god function flags) are cheap and depend on the
+//     thresholds, so they are never cached and are recomputed from the current config on every run.
+
+// cacheFormat versions the cached metrics. Bump it whenever the analyzer adds or changes a metric,
+// so that development builds sharing a version number do not reuse stale entries.
+const cacheFormat = 15
+
+// cacheEntry is the on-disk representation of the raw metrics of one file
+type cacheEntry struct {
+	CommentDensity float64                 `json:"commentDensity"`
+	Results        []analyzer.MethodResult `json:"results"`
+}
+
+// cacheDir returns the directory holding cached analysis results
+func cacheDir() (string, error) {
+	if dir := os.Getenv("ZEDS_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "zeds"), nil
+}
+
+// cacheKey derives the cache key from the file content, the zeds version and the analyzer options
+func cacheKey(src []byte, opts analyzer.Options) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%d\x00%v\x00%d\x00%s\x00%s\x00%v\x00%d\x00", GetVersion(), ReportSchemaVersion, cacheFormat,
+		opts.CommentDensityMultiplier, opts.ParserMode, opts.HalsteadProfile, opts.MIVariant, opts.MagicNumberAllowlist, opts.TabWidth)
+	h.Write(src)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// analyzeCached returns the raw metrics of a source file, reusing a cached entry when the key matches.
+// Cache failures are not fatal: the source is simply analyzed again.
+func analyzeCached(name string, src []byte, opts analyzer.Options) ([]analyzer.MethodResult, float64, error) {
+	dir, dirErr := cacheDir()
+	path := filepath.Join(dir, cacheKey(src, opts)+".json")
+	if dirErr == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			var entry cacheEntry
+			if json.Unmarshal(data, &entry) == nil {
+				return entry.Results, entry.CommentDensity, nil
+			}
+		}
+	}
+
+	results, commentDensity, err := analyzer.AnalyzeSource(name, src, opts)
+	if err != nil || dirErr != nil {
+		return results, commentDensity, err
+	}
+	if data, err := json.Marshal(cacheEntry{CommentDensity: commentDensity, Results: results}); err == nil {
+		if os.MkdirAll(dir, 0755) == nil {
+			_ = os.WriteFile(path, data, 0644)
+		}
+	}
+	return results, commentDensity, nil
+}
@@ -3,9 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/fatihaydin9/zeds/analyzer"
 )
@@ -25,124 +25,288 @@ const (
 	ItalicReset  = "\x1b[23m"
 )
 
-// Config defines the thresholds and settings for code analysis
-type Config struct {
-	Cyclomatic struct {
-		Medium float64 `json:"medium"`
-		High   float64 `json:"high"`
-	} `json:"cyclomatic"`
-	MaintainabilityIndex struct {
-		Low    float64 `json:"low"`
-		Medium float64 `json:"medium"`
-	} `json:"maintainabilityIndex"`
-	LOC struct {
-		Medium float64 `json:"medium"`
-		High   float64 `json:"high"`
-	} `json:"loc"`
-	CommentDensityMultiplier float64 `json:"commentDensityMultiplier"`
-}
-
-var (
-	defaultConfig = Config{
-		CommentDensityMultiplier: 5,
-	}
-	configPath = filepath.Join(".", "config.json")
-)
+// printHelp displays a detailed help message.
+func PrintHelp() {
+	fmt.Fprintln(stdout, Bold+ColorBlue+"==========================================================="+ColorReset)
+	fmt.Fprintln(stdout, Bold+ColorMagenta+"              Zeds Code Quality Analyzer            "+ColorReset)
+	fmt.Fprintln(stdout, Bold+ColorBlue+"==========================================================="+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, Bold+ColorBlue+"Usage:"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds help"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Display this help message"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds version [--format json]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Show the version, commit, build date, Go version and report schema version"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds doctor"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Show the version, environment and effective configuration"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds init [--format yaml|toml|json] [--force] [<file>]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Create a configuration file with the default values, commented in YAML and TOML (zeds.yaml by default)"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds config set <key> <value>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Update a configuration value by its dotted key, in the profile selected with --profile if given; lists are separated by commas"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds config set cyclomatic.high 12"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds config get <key>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Show the effective value of a configuration key, objects as JSON"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds config get cyclomatic"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds config list"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- List every effective configuration value as key = value"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds configure -t <metric> <value1> <value2>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Deprecated, use config set. Update metric thresholds (Valid metrics: "+ColorGreen+"cyclomatic, npath, abc, nesting, maintainabilityIndex, loc, lloc, indentation, localVars, params, results, exits, magicNumbers, errorDensity, fanOut, concurrency, distance, lcom, wmc, structFields, interfaceMethods, goroutines, defers, thirdPartyImports"+ColorWhite+")"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds configure -t cyclomatic 6 10"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds configure -d <value>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Deprecated, use config set commentDensityMultiplier. Update the comment density multiplier"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds configure -d 7"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath}"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze the specified Go source file"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -f main.go"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} {go filePath}... | -f '{glob}'"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze several files, or the files matching a glob (** matches any directories), with a combined summary"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -f 'internal/**/*.go'"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} [--recursive]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze every Go file in the directory (and its subdirectories with --recursive)"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  and print per-file results, aggregate results and the package comment density"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -d . --recursive"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --exclude <pattern>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Skip matching paths when analyzing several files (patterns are also read from .zedsignore)"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -d . -r --exclude vendor/ --exclude '*.pb.go'"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -p {package pattern}"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze the Go packages matching the pattern, grouping results by package"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -p ./..."+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze --repo {url[@ref]}"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Shallow-clone a git repository into a temporary directory and analyze it recursively"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze --repo https://github.com/org/project@v1.2.0"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze --archive {file}"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze the Go files in a .zip, .tar, .tar.gz or .tgz archive without unpacking it"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze --archive v1.2.0.zip"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze --diff {base-ref}"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Report only the functions added or modified since a git ref, e.g. as a pull request gate"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze --diff origin/main --fail-on results"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze --stdin"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze Go source read from standard input (also: zeds analyze -)"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"cat main.go | zeds analyze -"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --budgets <file>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Fail when a function exceeds its cyclomatic complexity budget"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -f main.go --budgets zeds-budgets.json"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --exported-only"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Report only exported functions and methods (the public API)"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} -v"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Verbose output with additional per-function details, and the timing, skipped files and type errors on stderr"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} -q"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Quiet output with only the threshold violations and the final verdict"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --lint"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Report advisory lint findings, such as functions returning an error that also panic"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --group-by-severity"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Group functions into RED, YELLOW and GREEN sections, worst first"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} [--min-cc N] [--min-loc N] [--max-mi N] [--only-violations]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Report only the functions at or past the given metric values, or that reach a threshold"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -d . -r --only-violations"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} [--sort cyclomatic|mi|loc|halstead] [--top N]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- List functions worst first by a metric, keeping only the N worst, and rank all files together"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -d . -r --top 10 --sort loc"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --format json"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write the report as JSON (see zeds schema)"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --format markdown [--markdown-all]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write aggregate metrics and the worst offenders (or all functions) as markdown for PR comments"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format ndjson"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Stream one JSON object per function as each file is analyzed"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format sarif"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write threshold violations as SARIF 2.1.0, e.g. for GitHub Code Scanning"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format junit"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write a JUnit XML report with one test case per function, failing at high thresholds"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format gitlab"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write threshold violations as a GitLab Code Quality report for merge request diffs"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format github-annotations"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Print threshold violations as GitHub Actions annotations on the affected lines"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format teamcity"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Print threshold violations as TeamCity service messages for the Inspections tab"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format sonar"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write threshold violations as a SonarQube generic issue report"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format tap"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write a TAP stream with one test per function, not ok at high thresholds"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format table"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Print one row per function with its main metrics in aligned columns"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --format <format> -o <file>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write the report of any format to a file instead of standard output, creating its directory"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -d . --format sarif -o reports/zeds.sarif"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --skip-resolution | --strict-parse"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Skip identifier resolution for speed on huge files, or report declaration errors"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --fail-on medium|high"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Exit with status 1 when a function reaches a medium or high threshold; errors exit with status 2"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -d . -r --fail-on high"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --fail-on results"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Exit with a non-zero status when a function has too many return values"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -d {directory} --fail-on panics"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Exit with a non-zero status when a function outside package main calls panic"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --cache"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Reuse cached metrics for unchanged files (thresholds are always applied fresh)"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --match <regexp>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Report only functions whose qualified name matches the regular expression"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds analyze -f server.go --match 'handle.*'"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds analyze -f {go filePath} --build-tags <tag,tag>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Skip files whose //go:build constraint does not match the given tags"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds schema"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Print the JSON Schema of the JSON report"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds compare <old.go> <new.go>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Compare aggregate metrics of two files side by side"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds compare old.go new.go"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds snippet '<go code>'"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Analyze a function declaration or bare function body given as an argument"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds snippet 'func f(x int) int { if x > 0 { return x }; return -x }'"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds list -f {go filePath}... | -d {directory} [-r] [--format json]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- List the functions, methods (with their receiver) and types of Go files with their locations"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds list -d ./... --format json"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds explain <metric> [<function> -f {go filePath}]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Describe how a metric is computed and show its intermediate values for a function"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds explain cyclomatic Parser.Parse -f parser.go"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds watch -f {go filePath}"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Re-analyze a file on every save, showing how each function changed"+ColorReset)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds watch -d {directory} [-r]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Re-analyze the files of a directory as they change, printing a delta of their metrics"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds watch -d ./..."+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds codeclimate [--config <engine config>] [--code <directory>]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Run as a Code Climate engine, reading /config.json and writing issues for /code to stdout"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds packages [--format text|json] [--baseline <report.json>] [-o <file>] [package pattern...]"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Print the coupling, instability, abstractness, main sequence distance,"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  documentation coverage and API surface of packages, along with import cycles and unused exports"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  --baseline compares the API surface with a previous JSON report of the command"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  Example: "+ColorYellow+"zeds packages ./..."+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds <command> -h"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- List the options of a command. Options have long names such as --recursive, some with a"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  one-letter alias such as -r; they may follow the arguments, take values as --format=json,"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"  and one-letter options may be combined as in -rv"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds <command> --color auto|always|never | --no-color"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Color the output always, never or, by default, only on a terminal when NO_COLOR is not set"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+ColorYellow+"zeds <command> --cpuprofile <file> --memprofile <file>"+ColorReset)
+	fmt.Fprintln(stdout, "      "+ColorWhite+"- Write a CPU profile and/or heap profile of the run for go tool pprof"+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, Bold+ColorBlue+"Description:"+ColorReset)
+	fmt.Fprintln(stdout, "Zeds analyzes Go source files to calculate key code quality metrics such as:")
+	fmt.Fprintln(stdout, "  - Cyclomatic Complexity")
+	fmt.Fprintln(stdout, "  - NPath Complexity")
+	fmt.Fprintln(stdout, "  - Nesting Depth")
+	fmt.Fprintln(stdout, "  - Halstead Volume")
+	fmt.Fprintln(stdout, "  - Lines of Code (LOC)")
+	fmt.Fprintln(stdout, "  - Logical Lines of Code (LLOC)")
+	fmt.Fprintln(stdout, "  - Indentation Complexity")
+	fmt.Fprintln(stdout, "  - ABC Size")
+	fmt.Fprintln(stdout, "  - Maintainability Index (MI)")
+	fmt.Fprintln(stdout, "  - Comment Density")
+	fmt.Fprintln(stdout, "  - Local Variables")
+	fmt.Fprintln(stdout, "  - Parameters")
+	fmt.Fprintln(stdout, "  - Return Values")
+	fmt.Fprintln(stdout, "  - Exit Points")
+	fmt.Fprintln(stdout, "  - Magic Numbers")
+	fmt.Fprintln(stdout, "  - Error Handling Density")
+	fmt.Fprintln(stdout, "  - Technical debt markers (TODO, FIXME, HACK, XXX) in comments")
+	fmt.Fprintln(stdout, "  - Fan-out")
+	fmt.Fprintln(stdout, "  - Concurrency Weight")
+	fmt.Fprintln(stdout, "  - Lack of Cohesion of Methods (LCOM) of types")
+	fmt.Fprintln(stdout, "  - Weighted Methods per Class (WMC) of types")
+	fmt.Fprintln(stdout, "  - Field count of structs and method count of interfaces")
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, Bold+ColorBlue+"Configuration:"+ColorReset)
+	fmt.Fprintln(stdout, "Configuration values are read from the "+ColorMagenta+"--config"+ColorReset+" file, or else from the first "+ColorMagenta+"zeds.json"+ColorReset+" or "+ColorMagenta+".zeds.json"+ColorReset+" (or .yaml, .yml, .toml)")
+	fmt.Fprintln(stdout, "found upward from the analyzed path to the repository root, or else from "+ColorMagenta+"config.json"+ColorReset+" in the current directory.")
+	fmt.Fprintln(stdout, "The global "+ColorMagenta+"--profile <name>"+ColorReset+" flag applies a named profile of the file, e.g. strict for CI.")
+	fmt.Fprintln(stdout, "ZEDS_ environment variables override values and analyze options, e.g. "+ColorMagenta+"ZEDS_CYCLOMATIC_HIGH=15"+ColorReset+" or "+ColorMagenta+"ZEDS_FORMAT=json"+ColorReset+".")
+	fmt.Fprintln(stdout, "Without a file, the default values below are used; "+ColorMagenta+"zeds init"+ColorReset+" creates a file with them:")
+	fmt.Fprintln(stdout)
+	defaults, _ := json.MarshalIndent(DefaultConfig(), "", "  ")
+	fmt.Fprintln(stdout, ColorGreen+string(defaults)+ColorReset)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, Bold+ColorBlue+"Keep your code clean and maintainable!"+ColorReset)
+	fmt.Fprintln(stdout)
+}
 
-func init() {
-	// Set default values
-	defaultConfig.Cyclomatic.Medium = 6
-	defaultConfig.Cyclomatic.High = 10
-	defaultConfig.MaintainabilityIndex.Low = 40
-	defaultConfig.MaintainabilityIndex.Medium = 60
-	defaultConfig.LOC.Medium = 20
-	defaultConfig.LOC.High = 40
-}
-
-// loadConfig reads the configuration file. If it does not exist, it creates one with default values.
-func LoadConfig() (*Config, error) {
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		data, err := json.MarshalIndent(defaultConfig, "", "  ")
-		if err != nil {
-			return nil, err
-		}
-		if err := os.WriteFile(configPath, data, 0644); err != nil {
-			return nil, err
-		}
-		return &defaultConfig, nil
-	}
-	
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+// GetColorForCyclomatic returns the color based on cyclomatic complexity thresholds
+func GetColorForCyclomatic(cc int, cfg *Config) string {
+	if float64(cc) >= cfg.Cyclomatic.High {
+		return ColorRed
+	} else if float64(cc) >= cfg.Cyclomatic.Medium {
+		return ColorYellow
 	}
-	return &cfg, nil
+	return ColorGreen
 }
 
-// SaveConfig writes the configuration to the config file.
-func SaveConfig(cfg *Config) error {
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
+// GetColorForNPath returns the color based on NPath complexity thresholds
+func GetColorForNPath(npath int64, cfg *Config) string {
+	if float64(npath) >= cfg.NPath.High {
+		return ColorRed
+	} else if float64(npath) >= cfg.NPath.Medium {
+		return ColorYellow
 	}
-	return os.WriteFile(configPath, data, 0644)
+	return ColorGreen
 }
 
-// printHelp displays a detailed help message.
-func PrintHelp() {
-	fmt.Println(Bold + ColorBlue + "===========================================================" + ColorReset)
-	fmt.Println(Bold + ColorMagenta + "              Zeds Code Quality Analyzer            " + ColorReset)
-	fmt.Println(Bold + ColorBlue + "===========================================================" + ColorReset)
-	fmt.Println()
-	fmt.Println(Bold + ColorBlue + "Usage:" + ColorReset)
-	fmt.Println()
-	fmt.Println("  " + ColorYellow + "zeds help" + ColorReset)
-	fmt.Println("      " + ColorWhite + "- Display this help message" + ColorReset)
-	fmt.Println()
-	fmt.Println("  " + ColorYellow + "zeds configure -t <metric> <value1> <value2>" + ColorReset)
-	fmt.Println("      " + ColorWhite + "- Update metric thresholds (Valid metrics: " + ColorGreen + "cyclomatic, maintainabilityIndex, loc" + ColorWhite + ")" + ColorReset)
-	fmt.Println("      " + ColorWhite + "  Example: " + ColorYellow + "zeds configure -t cyclomatic 6 10" + ColorReset)
-	fmt.Println()
-	fmt.Println("  " + ColorYellow + "zeds configure -d <value>" + ColorReset)
-	fmt.Println("      " + ColorWhite + "- Update the comment density multiplier" + ColorReset)
-	fmt.Println("      " + ColorWhite + "  Example: " + ColorYellow + "zeds configure -d 7" + ColorReset)
-	fmt.Println()
-	fmt.Println("  " + ColorYellow + "zeds analyze -f {go filePath}" + ColorReset)
-	fmt.Println("      " + ColorWhite + "- Analyze the specified Go source file" + ColorReset)
-	fmt.Println("      " + ColorWhite + "  Example: " + ColorYellow + "zeds analyze -f main.go" + ColorReset)
-	fmt.Println()
-	fmt.Println(Bold + ColorBlue + "Description:" + ColorReset)
-	fmt.Println("Zeds analyzes Go source files to calculate key code quality metrics such as:")
-	fmt.Println("  - Cyclomatic Complexity")
-	fmt.Println("  - Halstead Volume")
-	fmt.Println("  - Lines of Code (LOC)")
-	fmt.Println("  - Maintainability Index (MI)")
-	fmt.Println("  - Comment Density")
-	fmt.Println()
-	fmt.Println(Bold + ColorBlue + "Configuration:" + ColorReset)
-	fmt.Println("Configuration values are stored in the " + ColorMagenta + "config.json" + ColorReset + " file in the current directory.")
-	fmt.Println("If the file does not exist, it will be created with default values:")
-	fmt.Println()
-	fmt.Println(ColorGreen + `{
-  "cyclomatic": { "medium": 6, "high": 10 },
-  "maintainabilityIndex": { "low": 40, "medium": 60 },
-  "loc": { "medium": 20, "high": 40 },
-  "commentDensityMultiplier": 5
-}` + ColorReset)
-	fmt.Println()
-	fmt.Println(Bold + ColorBlue + "Keep your code clean and maintainable!" + ColorReset)
-	fmt.Println()
+// GetColorForABC returns the color based on ABC size score thresholds
+func GetColorForABC(score float64, cfg *Config) string {
+	if score >= cfg.ABC.High {
+		return ColorRed
+	} else if score >= cfg.ABC.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
 }
 
-// GetColorForCyclomatic returns the color based on cyclomatic complexity thresholds
-func GetColorForCyclomatic(cc int, cfg *Config) string {
-	if float64(cc) >= cfg.Cyclomatic.High {
+// GetColorForNesting returns the color based on maximum nesting depth thresholds
+func GetColorForNesting(depth int, cfg *Config) string {
+	if float64(depth) >= cfg.Nesting.High {
 		return ColorRed
-	} else if float64(cc) >= cfg.Cyclomatic.Medium {
+	} else if float64(depth) >= cfg.Nesting.Medium {
 		return ColorYellow
 	}
 	return ColorGreen
@@ -168,173 +332,670 @@ func GetColorForLOC(loc int, cfg *Config) string {
 	return ColorGreen
 }
 
-// handleAnalyzeCommand processes the analyze command
-func handleAnalyzeCommand(args []string) {
-	if len(args) < 3 || args[1] != "-f" {
-		fmt.Println(ColorRed + "Usage: zeds analyze -f {go filePath}" + ColorReset)
-		os.Exit(1)
+// GetColorForLLOC returns the color based on logical lines of code thresholds
+func GetColorForLLOC(lloc int, cfg *Config) string {
+	if float64(lloc) >= cfg.LLOC.High {
+		return ColorRed
+	} else if float64(lloc) >= cfg.LLOC.Medium {
+		return ColorYellow
 	}
-	
-	filePath := args[2]
-	absPath, err := filepath.Abs(filePath)
-	if err != nil {
-		fmt.Println(ColorRed + "Error resolving file path: " + err.Error() + ColorReset)
-		os.Exit(1)
+	return ColorGreen
+}
+
+// GetColorForIndentation returns the color based on indentation complexity thresholds
+func GetColorForIndentation(indentation int, cfg *Config) string {
+	if float64(indentation) >= cfg.Indentation.High {
+		return ColorRed
+	} else if float64(indentation) >= cfg.Indentation.Medium {
+		return ColorYellow
 	}
+	return ColorGreen
+}
 
-	cfg, err := LoadConfig()
-	if err != nil {
-		fmt.Println(ColorRed + "Error loading config: " + err.Error() + ColorReset)
-		os.Exit(1)
+// GetColorForLength returns the color of a function's length in the configured length metric
+func GetColorForLength(res analyzer.MethodResult, cfg *Config) string {
+	if cfg.FunctionLength == FunctionLengthLLOC {
+		return GetColorForLLOC(res.LLOC, cfg)
 	}
+	return GetColorForLOC(res.LOC, cfg)
+}
 
-	printHeader()
-	analyzeAndPrintResults(absPath, cfg)
+// lengthRatio returns a function's length in the configured length metric relative to its high threshold
+func lengthRatio(res analyzer.MethodResult, cfg *Config) float64 {
+	if cfg.FunctionLength == FunctionLengthLLOC {
+		return float64(res.LLOC) / cfg.LLOC.High
+	}
+	return float64(res.LOC) / cfg.LOC.High
 }
 
-// handleConfigureCommand processes the configure command
-func handleConfigureCommand(args []string) {
-	if len(args) < 3 {
-		fmt.Println(ColorRed + "Usage:\n  zeds configure -t <metric> <value1> <value2>\n  zeds configure -d <value>" + ColorReset)
-		os.Exit(1)
+// GetColorForLocalVars returns the color based on local variable count thresholds
+func GetColorForLocalVars(vars int, cfg *Config) string {
+	if float64(vars) >= cfg.LocalVars.High {
+		return ColorRed
+	} else if float64(vars) >= cfg.LocalVars.Medium {
+		return ColorYellow
 	}
+	return ColorGreen
+}
 
-	cfg, err := LoadConfig()
-	if err != nil {
-		fmt.Println(ColorRed + "Error loading config: " + err.Error() + ColorReset)
-		os.Exit(1)
+// GetColorForParams returns the color based on parameter count thresholds
+func GetColorForParams(params int, cfg *Config) string {
+	if float64(params) >= cfg.Params.High {
+		return ColorRed
+	} else if float64(params) >= cfg.Params.Medium {
+		return ColorYellow
 	}
+	return ColorGreen
+}
 
-	switch args[1] {
-	case "-d":
-		handleDensityConfig(args, cfg)
-	case "-t":
-		handleThresholdConfig(args, cfg)
-	default:
-		fmt.Println(ColorRed + "Usage:\n  zeds configure -t <metric> <value1> <value2>\n  zeds configure -d <value>" + ColorReset)
-		os.Exit(1)
+// GetColorForResults returns the color based on return value count thresholds
+func GetColorForResults(results int, cfg *Config) string {
+	if float64(results) >= cfg.Results.High {
+		return ColorRed
+	} else if float64(results) >= cfg.Results.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForExits returns the color based on exit point count thresholds
+func GetColorForExits(exits int, cfg *Config) string {
+	if float64(exits) >= cfg.Exits.High {
+		return ColorRed
+	} else if float64(exits) >= cfg.Exits.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForMagicNumbers returns the color based on magic number count thresholds
+func GetColorForMagicNumbers(magicNumbers int, cfg *Config) string {
+	if float64(magicNumbers) >= cfg.MagicNumbers.High {
+		return ColorRed
+	} else if float64(magicNumbers) >= cfg.MagicNumbers.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForErrorDensity returns the color based on error handling density thresholds
+func GetColorForErrorDensity(density float64, cfg *Config) string {
+	if density >= cfg.ErrorDensity.High {
+		return ColorRed
+	} else if density >= cfg.ErrorDensity.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForFanOut returns the color based on fan-out thresholds
+func GetColorForFanOut(fanOut int, cfg *Config) string {
+	if float64(fanOut) >= cfg.FanOut.High {
+		return ColorRed
+	} else if float64(fanOut) >= cfg.FanOut.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForDistance returns the color based on the main sequence distance thresholds of a package
+func GetColorForDistance(distance float64, cfg *Config) string {
+	if distance >= cfg.Distance.High {
+		return ColorRed
+	} else if distance >= cfg.Distance.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForDocCoverage returns red for a package documentation coverage below the configured minimum
+func GetColorForDocCoverage(percent float64, cfg *Config) string {
+	if percent < cfg.DocCoverage.Minimum {
+		return ColorRed
+	}
+	return ColorGreen
+}
+
+// GetColorForLCOM returns the color based on the lack of cohesion thresholds of a type
+func GetColorForLCOM(lcom float64, cfg *Config) string {
+	if lcom >= cfg.LCOM.High {
+		return ColorRed
+	} else if lcom >= cfg.LCOM.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForWMC returns the color based on the weighted methods thresholds of a type
+func GetColorForWMC(wmc int, cfg *Config) string {
+	if float64(wmc) >= cfg.WMC.High {
+		return ColorRed
+	} else if float64(wmc) >= cfg.WMC.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForStructFields returns the color based on the field count thresholds of a struct type
+func GetColorForStructFields(fields int, cfg *Config) string {
+	if float64(fields) >= cfg.StructFields.High {
+		return ColorRed
+	} else if float64(fields) >= cfg.StructFields.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForInterfaceMethods returns the color based on the method count thresholds of an interface type
+func GetColorForInterfaceMethods(methods int, cfg *Config) string {
+	if float64(methods) >= cfg.InterfaceMethods.High {
+		return ColorRed
+	} else if float64(methods) >= cfg.InterfaceMethods.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForConcurrency returns the color based on concurrency weight thresholds
+func GetColorForConcurrency(weight int, cfg *Config) string {
+	if float64(weight) >= cfg.Concurrency.High {
+		return ColorRed
+	} else if float64(weight) >= cfg.Concurrency.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForGoroutines returns the color based on the optional goroutine count thresholds
+func GetColorForGoroutines(goroutines int, cfg *Config) string {
+	if cfg.Goroutines.High <= 0 {
+		return ColorGreen
+	} else if float64(goroutines) >= cfg.Goroutines.High {
+		return ColorRed
+	} else if cfg.Goroutines.Medium > 0 && float64(goroutines) >= cfg.Goroutines.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForThirdPartyImports returns the color based on the optional third-party import thresholds
+func GetColorForThirdPartyImports(imports int, cfg *Config) string {
+	if cfg.ThirdPartyImports.High <= 0 {
+		return ColorGreen
+	} else if float64(imports) >= cfg.ThirdPartyImports.High {
+		return ColorRed
+	} else if cfg.ThirdPartyImports.Medium > 0 && float64(imports) >= cfg.ThirdPartyImports.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForDefers returns the color based on the optional defer count thresholds
+func GetColorForDefers(defers int, cfg *Config) string {
+	if cfg.Defers.High <= 0 {
+		return ColorGreen
+	} else if float64(defers) >= cfg.Defers.High {
+		return ColorRed
+	} else if cfg.Defers.Medium > 0 && float64(defers) >= cfg.Defers.Medium {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// GetColorForPanics returns red for a function outside package main that calls panic,
+// the same functions --fail-on panics reports
+func GetColorForPanics(res analyzer.MethodResult) string {
+	if res.Panics > 0 && res.Package != "main" {
+		return ColorRed
+	}
+	return ColorGreen
+}
+
+// IsGodFunction reports whether a function is bad across the board according to the god function rule,
+// reusing the high cyclomatic, high length (LOC or LLOC) and low MI thresholds
+func IsGodFunction(res analyzer.MethodResult, cfg *Config) bool {
+	if !cfg.GodFunction.Enabled {
+		return false
+	}
+	hits := 0
+	if float64(res.Cyclomatic) >= cfg.Cyclomatic.High {
+		hits++
+	}
+	if lengthRatio(res, cfg) >= 1 {
+		hits++
+	}
+	if res.MaintainabilityIndex < cfg.MaintainabilityIndex.Low {
+		hits++
+	}
+	if cfg.GodFunction.Mode == GodFunctionTwoOfThree {
+		return hits >= 2
+	}
+	return hits == 3
+}
+
+// classifyResults fills in the fields of the results that depend on the configuration
+func classifyResults(results []analyzer.MethodResult, cfg *Config) {
+	for i := range results {
+		results[i].IsGodFunction = IsGodFunction(results[i], cfg)
+	}
+}
+
+// nextArg returns the value following the option at position *i and advances past it
+func nextArg(args []string, i *int) (string, error) {
+	if *i+1 >= len(args) {
+		return "", fmt.Errorf("missing value for %s", args[*i])
+	}
+	*i++
+	return args[*i], nil
+}
+
+// containsString reports whether the slice contains the string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// handleConfigureCommand processes the configure command, which config set replaces
+func handleConfigureCommand(args []string) {
+	var metric, density string
+	flags := newFlagSet("configure", "-t <metric> <value1> <value2>", "-d <value>")
+	flags.stringVar(&metric, "threshold", "t", "update the medium and high thresholds of the `metric`")
+	flags.stringVar(&density, "density", "d", "update the comment density multiplier to the `value`")
+	values := parseCommandArgs(flags, args[1:])
+	if (metric == "") == (density == "") {
+		failUsage(flags, fmt.Errorf("specify either -t or -d"))
+	}
+	if metric != "" && len(values) != 2 || density != "" && len(values) != 0 {
+		failUsage(flags, fmt.Errorf("-t takes a metric and two values, -d a single value"))
+	}
+	fmt.Fprintln(stderr, ColorYellow+"configure is deprecated, use zeds config set <key> <value> instead"+ColorReset)
+
+	if density != "" {
+		handleDensityConfig(density)
+	} else {
+		handleThresholdConfig(metric, values[0], values[1])
 	}
 }
 
 // handleDensityConfig handles the density multiplier configuration
-func handleDensityConfig(args []string, cfg *Config) {
-	multiplier, err := strconv.ParseFloat(args[2], 64)
+func handleDensityConfig(value string) {
+	multiplier, err := strconv.ParseFloat(value, 64)
 	if err != nil {
-		fmt.Println(ColorRed + "Error: <value> must be numeric." + ColorReset)
-		os.Exit(1)
+		fmt.Fprintln(stderr, ColorRed+"Error: <value> must be numeric."+ColorReset)
+		exit(exitError)
 	}
-	
-	cfg.CommentDensityMultiplier = multiplier
-	if err := SaveConfig(cfg); err != nil {
-		fmt.Println(ColorRed + "Failed to save config: " + err.Error() + ColorReset)
-		os.Exit(1)
+
+	if err := updateConfig(configUpdate{[]string{"commentDensityMultiplier"}, multiplier}); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Failed to save config: "+err.Error()+ColorReset)
+		exit(exitError)
 	}
-	
-	fmt.Println(ColorGreen + "Comment density multiplier updated to:", multiplier, ColorReset)
+
+	fmt.Fprintln(stdout, ColorGreen+"Comment density multiplier updated to:", multiplier, ColorReset)
 }
 
 // handleThresholdConfig handles the threshold configuration
-func handleThresholdConfig(args []string, cfg *Config) {
-	if len(args) < 5 {
-		fmt.Println(ColorRed + "Usage: zeds configure -t <metric> <value1> <value2>" + ColorReset)
-		os.Exit(1)
-	}
-
-	value1, value2, err := parseThresholdValues(args[3], args[4])
+func handleThresholdConfig(metric, val1, val2 string) {
+	value1, value2, err := parseThresholdValues(val1, val2)
 	if err != nil {
-		fmt.Println(ColorRed + "Error: <value1> and <value2> must be numeric." + ColorReset)
-		os.Exit(1)
+		fmt.Fprintln(stderr, ColorRed+"Error: <value1> and <value2> must be numeric."+ColorReset)
+		exit(exitError)
 	}
 
-	if err := updateThresholds(cfg, args[2], value1, value2); err != nil {
-		fmt.Println(ColorRed + err.Error() + ColorReset)
-		os.Exit(1)
+	// updateThresholds only checks the metric here; the file is edited by key.
+	cfg := DefaultConfig()
+	if err := updateThresholds(&cfg, metric, value1, value2); err != nil {
+		fmt.Fprintln(stderr, ColorRed+err.Error()+ColorReset)
+		exit(exitError)
 	}
 
-	if err := SaveConfig(cfg); err != nil {
-		fmt.Println(ColorRed + "Failed to save config: " + err.Error() + ColorReset)
-		os.Exit(1)
+	low, high := "medium", "high"
+	if metric == "maintainabilityIndex" {
+		low, high = "low", "medium"
+	}
+	err = updateConfig(configUpdate{[]string{metric, low}, value1}, configUpdate{[]string{metric, high}, value2})
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Failed to save config: "+err.Error()+ColorReset)
+		exit(exitError)
 	}
 
-	fmt.Printf(ColorGreen+"Configuration updated for '%s': %v and %v\n"+ColorReset, args[2], value1, value2)
+	fmt.Fprintf(stdout, ColorGreen+"Configuration updated for '%s': %v and %v\n"+ColorReset, metric, value1, value2)
 }
 
 // Run executes the CLI application with the given arguments
 func Run(args []string) {
 	// Remove the program name from args
-	args = args[1:]
-	
-	if len(args) == 0 {
-		fmt.Println(ColorRed + "Usage:\n  zeds help\n  zeds configure -t <metric> <value1> <value2>\n  zeds configure -d <value>\n  zeds analyze -f {go filePath}" + ColorReset)
-		os.Exit(1)
+	args, err := extractColorFlags(args[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if args, err = extractConfigFlags(args); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
 	}
+	locateConfig(".")
+	applyTheme()
+	args, cpuProfile, memProfile, err := extractProfileFlags(args)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if err := startProfiling(cpuProfile, memProfile); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error starting profiler: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	defer runExitHooks()
 
-	switch args[0] {
-	case "help":
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, ColorRed+commandsUsage()+ColorReset)
+		exit(exitError)
+	}
+	if args[0] == "-h" || args[0] == "--help" {
 		PrintHelp()
-	case "configure":
-		handleConfigureCommand(args)
-	case "analyze":
-		handleAnalyzeCommand(args)
-	default:
-		fmt.Println(ColorRed + "Unknown command. Valid commands: help, configure, analyze" + ColorReset)
-		os.Exit(1)
+		return
 	}
+	if args[0] == "--version" {
+		handleVersionCommand(args)
+		return
+	}
+
+	var names []string
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			cmd.run(args)
+			return
+		}
+		names = append(names, cmd.name)
+	}
+	fmt.Fprintln(stderr, ColorRed+"Unknown command. Valid commands: "+strings.Join(names, ", ")+ColorReset)
+	exit(exitError)
+}
+
+// commands lists the commands of zeds in the order of the usage. Each command is run with the
+// arguments starting at its name.
+var commands = []struct {
+	name    string
+	summary string
+	run     func(args []string)
+}{
+	{"help", "display the detailed help", handleHelpCommand},
+	{"version", "show the version and build metadata", handleVersionCommand},
+	{"doctor", "show the version, environment and effective configuration", handleDoctorCommand},
+	{"init", "create a commented configuration file with the default values", handleInitCommand},
+	{"config", "get, set or list configuration values by key", handleConfigCommand},
+	{"configure", "update metric thresholds or the comment density multiplier (deprecated, use config)", handleConfigureCommand},
+	{"analyze", "analyze Go files, directories, packages or repositories", handleAnalyzeCommand},
+	{"compare", "compare the metrics of two files", handleCompareCommand},
+	{"snippet", "analyze a function given as an argument", handleSnippetCommand},
+	{"schema", "print the JSON Schema of the JSON report", handleSchemaCommand},
+	{"list", "list the functions, methods and types of Go files with their locations", handleListCommand},
+	{"explain", "describe how a metric is computed, for a function if given", handleExplainCommand},
+	{"watch", "re-analyze files every time they are saved", handleWatchCommand},
+	{"codeclimate", "run as a Code Climate engine", handleCodeClimateCommand},
+	{"packages", "report package metrics, import cycles and unused exports", handlePackagesCommand},
+}
+
+// commandsUsage returns the usage of zeds, listing its commands
+func commandsUsage() string {
+	var b strings.Builder
+	b.WriteString("Usage: zeds <command> [options] [arguments]\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "\n  %-12s %s", cmd.name, cmd.summary)
+	}
+	b.WriteString("\nRun 'zeds <command> -h' to list the options of a command.")
+	return b.String()
+}
+
+// handleHelpCommand processes the help command
+func handleHelpCommand(args []string) {
+	parseCommandOptions(newFlagSet("help", ""), args[1:])
+	PrintHelp()
 }
 
 // printHeader prints the application header
 func printHeader() {
-	fmt.Println(Bold + ColorBlue + "===========================================================" + ColorReset)
-	fmt.Println(Bold + ColorMagenta + "              Zeds Code Quality Analyzer            " + ColorReset)
-	fmt.Println(Bold + ColorBlue + "===========================================================" + ColorReset)
-	fmt.Println()
+	fmt.Fprintln(stdout, Bold+ColorBlue+"==========================================================="+ColorReset)
+	fmt.Fprintln(stdout, Bold+ColorMagenta+"              Zeds Code Quality Analyzer            "+ColorReset)
+	fmt.Fprintln(stdout, Bold+ColorBlue+"==========================================================="+ColorReset)
+	fmt.Fprintln(stdout)
 }
 
-// analyzeAndPrintResults performs the analysis and prints the results
-func analyzeAndPrintResults(filePath string, cfg *Config) {
-	results, commentDensity, err := analyzer.AnalyzeMethods(filePath, cfg.CommentDensityMultiplier)
+// analyzeSource analyzes Go source, classifies the results and applies the result filters.
+// It returns the results, the file comment density and the number of excluded functions, or the
+// error of a file that does not parse.
+func analyzeSource(name string, src []byte, cfg *Config, opts analyzeOptions) ([]analyzer.MethodResult, float64, int, error) {
+	analyzerOpts := cfg.AnalyzerOptions()
+	analyzerOpts.ParserMode |= opts.parserMode
+	var results []analyzer.MethodResult
+	var commentDensity float64
+	var err error
+	if opts.cache {
+		results, commentDensity, err = analyzeCached(name, src, analyzerOpts)
+	} else {
+		results, commentDensity, err = analyzer.AnalyzeSource(name, src, analyzerOpts)
+	}
 	if err != nil {
-		fmt.Println(ColorRed + "Error during analysis: " + err.Error() + ColorReset)
-		os.Exit(1)
+		return nil, 0, 0, err
+	}
+
+	classifyResults(results, cfg)
+
+	excluded := 0
+	if opts.exportedOnly {
+		results, excluded = filterExported(results)
 	}
+	if opts.match != nil {
+		results = filterMatching(results, opts.match)
+	}
+	if opts.changedLines != nil {
+		results = filterChanged(results, opts.changedLines[name])
+	}
+	if filtersThresholds(opts) {
+		results = filterThresholds(results, cfg, opts)
+	}
+	return results, commentDensity, excluded, nil
+}
 
-	cdPercent := commentDensity * 100
+// filterMatching keeps only the functions whose qualified name matches the pattern
+func filterMatching(results []analyzer.MethodResult, pattern *regexp.Regexp) []analyzer.MethodResult {
+	var matching []analyzer.MethodResult
+	for _, res := range results {
+		if pattern.MatchString(res.QualifiedName()) {
+			matching = append(matching, res)
+		}
+	}
+	return matching
+}
+
+// printFileResults prints the text report for a single file
+func printFileResults(results []analyzer.MethodResult, commentDensity float64, excluded int, cfg *Config, opts analyzeOptions) {
 	if len(results) == 0 {
-		fmt.Println(ColorRed + "No functions found in the file." + ColorReset)
+		if opts.changedLines != nil {
+			fmt.Fprintln(stdout, ColorGreen+"No functions were added or modified in the file."+ColorReset)
+		} else if filtersThresholds(opts) {
+			fmt.Fprintln(stdout, ColorGreen+"No functions pass the --min-cc, --min-loc, --max-mi and --only-violations filters."+ColorReset)
+		} else if opts.match != nil {
+			fmt.Fprintln(stdout, ColorRed+fmt.Sprintf("No functions match the pattern '%s'.", opts.match)+ColorReset)
+		} else if opts.exportedOnly {
+			fmt.Fprintln(stdout, ColorRed+fmt.Sprintf("No exported functions found in the file (%d unexported excluded).", excluded)+ColorReset)
+		} else {
+			fmt.Fprintln(stdout, ColorRed+"No functions found in the file."+ColorReset)
+		}
 		return
 	}
 
-	printAnalysisResults(results, cdPercent, cfg)
+	if opts.exportedOnly {
+		fmt.Fprintln(stdout, Italic+ColorYellow+fmt.Sprintf("Excluded %d unexported function(s).", excluded)+ItalicReset+ColorReset)
+	}
+	printAnalysisResults(results, commentDensity*100, cfg, opts)
 }
 
-// printAnalysisResults prints the analysis results
-func printAnalysisResults(results []analyzer.MethodResult, commentDensity float64, cfg *Config) {
-	fmt.Println(Italic + ColorYellow + fmt.Sprintf("Calculated Comment Density (%%): %.1f", commentDensity) + ItalicReset + ColorReset)
-	fmt.Println()
-	fmt.Println(ColorCyan + "Analysis Results:" + ColorReset)
-	fmt.Println(ColorCyan + "------------------------------------------" + ColorReset)
-	
+// filtersThresholds reports whether functions are filtered by their metrics with --min-cc,
+// --min-loc, --max-mi or --only-violations
+func filtersThresholds(opts analyzeOptions) bool {
+	return opts.minCC > 0 || opts.minLOC > 0 || opts.maxMI != nil || opts.onlyBreach
+}
+
+// filterThresholds returns the results that pass all the metric filters of the options
+func filterThresholds(results []analyzer.MethodResult, cfg *Config, opts analyzeOptions) []analyzer.MethodResult {
+	var kept []analyzer.MethodResult
+	for _, res := range results {
+		if res.Cyclomatic < opts.minCC || res.LOC < opts.minLOC {
+			continue
+		}
+		if opts.maxMI != nil && res.MaintainabilityIndex > *opts.maxMI {
+			continue
+		}
+		if opts.onlyBreach && GetSeverity(res, cfg) == SeverityGreen {
+			continue
+		}
+		kept = append(kept, res)
+	}
+	return kept
+}
+
+// filterExported keeps only exported functions and methods and returns how many were excluded
+func filterExported(results []analyzer.MethodResult) ([]analyzer.MethodResult, int) {
+	var exported []analyzer.MethodResult
 	for _, res := range results {
-		printMethodResult(res, cfg)
+		if res.IsExported() {
+			exported = append(exported, res)
+		}
 	}
+	return exported, len(results) - len(exported)
+}
 
-	fmt.Println()
-	fmt.Println(ColorYellow + "Keep your code clean and maintainable!" + ColorReset)
-	fmt.Println(ColorMagenta + "Happy coding with Zeds!" + ColorReset)
+// uniformityWarning returns a warning when the metrics barely vary across many functions,
+// which suggests a generated file, or an empty string otherwise
+func uniformityWarning(results []analyzer.MethodResult, cfg *Config) string {
+	if len(results) < cfg.UniformMetrics.MinFunctions {
+		return ""
+	}
+	var cc, mi []float64
+	for _, res := range results {
+		cc = append(cc, float64(res.Cyclomatic))
+		mi = append(mi, res.MaintainabilityIndex)
+	}
+	ccVariance, miVariance := analyzer.Variance(cc), analyzer.Variance(mi)
+	if ccVariance > cfg.UniformMetrics.MaxVariance || miVariance > cfg.UniformMetrics.MaxVariance {
+		return ""
+	}
+	return fmt.Sprintf("Metrics are nearly uniform across %d functions (cyclomatic variance %.2f, MI variance %.2f). "+
+		"The file may be generated, in which case these numbers say little about its quality.", len(results), ccVariance, miVariance)
+}
+
+// printAnalysisResults prints the analysis results
+func printAnalysisResults(results []analyzer.MethodResult, commentDensity float64, cfg *Config, opts analyzeOptions) {
+	fmt.Fprintln(stdout, Italic+ColorYellow+fmt.Sprintf("Calculated Comment Density (%%): %.1f", commentDensity)+ItalicReset+ColorReset)
+	if opts.verbose {
+		if warning := uniformityWarning(results, cfg); warning != "" {
+			fmt.Fprintln(stdout, ColorYellow+"⚠ "+warning+ColorReset)
+		}
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"Analysis Results:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+
+	listed := rankResults(results, opts)
+	if ranksResults(opts) {
+		fmt.Fprintln(stdout, Italic+ColorYellow+rankingNote(len(listed), len(results), opts)+ItalicReset+ColorReset)
+	}
+	if opts.bySeverity {
+		printGroupedResults(listed, cfg, opts)
+	} else {
+		for _, res := range listed {
+			printMethodResult(res, cfg, opts)
+		}
+	}
+
+	score := CalculateScore(results, cfg)
+	fmt.Fprintln(stdout, Bold+"Quality Score:"+ColorReset, GetColorForScore(score, cfg), fmt.Sprintf("%.1f", score), ColorReset, "(function average)")
+	// The file is graded on its file score only, so that the grade matches the one of its heading.
+	fileScore := CalculateFileScore(results, cfg)
+	fmt.Fprintln(stdout, Bold+"File grade:"+ColorReset, GetColorForScore(fileScore, cfg), GetGradeForScore(fileScore, cfg), ColorReset,
+		fmt.Sprintf("(file score %.1f, the average of the quality score and the worst function)", fileScore))
+	fmt.Fprintln(stdout, Bold+"Technical debt:"+ColorReset, formatDebt(EstimateTechnicalDebt(results, cfg)))
+}
+
+// printFooter prints the closing message of a report
+func printFooter() {
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorYellow+"Keep your code clean and maintainable!"+ColorReset)
+	fmt.Fprintln(stdout, ColorMagenta+"Happy coding with Zeds!"+ColorReset)
 }
 
 // printMethodResult prints the result for a single method
-func printMethodResult(res analyzer.MethodResult, cfg *Config) {
+func printMethodResult(res analyzer.MethodResult, cfg *Config, opts analyzeOptions) {
 	ccColor := GetColorForCyclomatic(res.Cyclomatic, cfg)
 	miColor := GetColorForMI(res.MaintainabilityIndex, cfg)
 	locColor := GetColorForLOC(res.LOC, cfg)
-	
-	fmt.Println("Function:", ColorCyan+res.MethodName+ColorReset)
-	fmt.Println(Bold+"Calculated Halstead Volume:"+ColorReset, fmt.Sprintf("%.2f", res.HalsteadVolume))
-	fmt.Println("  - Cyclomatic Complexity:", ccColor, res.Cyclomatic, ColorReset)
-	fmt.Println("  - Lines of Code (LOC):", locColor, res.LOC, ColorReset)
-	fmt.Println("  - Maintainability Index:", miColor, fmt.Sprintf("%.2f", res.MaintainabilityIndex), ColorReset)
-	fmt.Println(ColorCyan + "------------------------------------------" + ColorReset)
+
+	if res.IsGodFunction {
+		fmt.Fprintln(stdout, "Function:", ColorCyan+res.QualifiedName()+ColorReset, Bold+ColorRed+"⚠ GOD FUNCTION"+ColorReset)
+	} else {
+		fmt.Fprintln(stdout, "Function:", ColorCyan+res.QualifiedName()+ColorReset)
+	}
+	if opts.previous != nil {
+		if prev, ok := opts.previous[res.QualifiedName()]; !ok {
+			fmt.Fprintln(stdout, "  "+ColorMagenta+"new since last save"+ColorReset)
+		} else if trend := formatTrend(prev, res); trend != "" {
+			fmt.Fprintln(stdout, "  "+trend)
+		}
+	}
+	fmt.Fprintln(stdout, Bold+"Calculated Halstead Volume:"+ColorReset, fmt.Sprintf("%.2f", res.HalsteadVolume))
+	printHalsteadMeasures(res.Halstead, cfg)
+	if opts.verbose {
+		h := res.Halstead
+		fmt.Fprintf(stdout, "      vocabulary: %d, length: %d, difficulty: %.2f, effort: %.2f, time: %.1fs, bugs: %.3f\n",
+			h.Vocabulary, h.Length, h.Difficulty, h.Effort, h.Time, h.Bugs)
+		fmt.Fprintf(stdout, "      level: %.3f, language level: %.2f, intelligence content: %.2f\n", h.Level, h.LanguageLevel, h.IntelligenceContent)
+	}
+	fmt.Fprintln(stdout, "  - Cyclomatic Complexity:", ccColor, res.Cyclomatic, ColorReset)
+	fmt.Fprintln(stdout, "  - NPath Complexity:", GetColorForNPath(res.NPath, cfg), res.NPath, ColorReset)
+	fmt.Fprintln(stdout, "  - Max Nesting Depth:", GetColorForNesting(res.MaxNesting, cfg), res.MaxNesting, ColorReset)
+	fmt.Fprintln(stdout, "  - Lines of Code (LOC):", locColor, res.LOC, ColorReset)
+	if opts.verbose {
+		fmt.Fprintf(stdout, "      code: %d, comments: %d, blank: %d\n", res.CodeLines, res.CommentLines, res.BlankLines)
+	}
+	fmt.Fprintln(stdout, "  - Logical Lines of Code (LLOC):", GetColorForLLOC(res.LLOC, cfg), res.LLOC, ColorReset)
+	fmt.Fprintln(stdout, "  - Indentation Complexity:", GetColorForIndentation(res.Indentation, cfg), res.Indentation, ColorReset)
+	fmt.Fprintln(stdout, "  - ABC Size:", GetColorForABC(res.ABC.Score, cfg), fmt.Sprintf("%.2f", res.ABC.Score), ColorReset)
+	if opts.verbose {
+		fmt.Fprintf(stdout, "      assignments: %d, branches: %d, conditions: %d\n", res.ABC.Assignments, res.ABC.Branches, res.ABC.Conditions)
+	}
+	fmt.Fprintln(stdout, "  - Local Variables:", GetColorForLocalVars(res.LocalVars, cfg), res.LocalVars, ColorReset)
+	fmt.Fprintln(stdout, "  - Parameters:", GetColorForParams(res.Params, cfg), res.Params, ColorReset)
+	fmt.Fprintln(stdout, "  - Return Values:", GetColorForResults(res.Results, cfg), res.Results, ColorReset)
+	fmt.Fprintln(stdout, "  - Exit Points:", GetColorForExits(res.Exits, cfg), res.Exits, ColorReset)
+	fmt.Fprintln(stdout, "  - Magic Numbers:", GetColorForMagicNumbers(res.MagicNumbers, cfg), res.MagicNumbers, ColorReset)
+	eh := res.ErrorHandling
+	fmt.Fprintln(stdout, "  - Error Handling Density:", GetColorForErrorDensity(eh.Density, cfg), fmt.Sprintf("%.2f", eh.Density), ColorReset,
+		fmt.Sprintf("(%d checks, %d error calls)", eh.Checks, eh.Calls))
+	if eh.Ignored {
+		fmt.Fprintln(stdout, "    "+ColorRed+"errors are assigned but never checked or used"+ColorReset)
+	}
+	// Fan-in and fan-out need type information, which only package analysis loads.
+	if opts.packages != nil {
+		fmt.Fprintln(stdout, "  - Fan-in:", res.FanIn)
+		fmt.Fprintln(stdout, "  - Fan-out:", GetColorForFanOut(res.FanOut, cfg), res.FanOut, ColorReset)
+	}
+	fmt.Fprintln(stdout, "  - Concurrency Weight:", GetColorForConcurrency(res.Concurrency.Weight, cfg), res.Concurrency.Weight, ColorReset)
+	if opts.verbose {
+		c := res.Concurrency
+		// The goroutine count has its own line below, so the breakdown leaves it out.
+		fmt.Fprintf(stdout, "      channel ops: %d, selects: %d, mutex ops: %d\n", c.ChannelOps, c.Selects, c.MutexOps)
+		fmt.Fprintln(stdout, "  - Goroutines:", GetColorForGoroutines(res.Goroutines, cfg), res.Goroutines, ColorReset)
+		fmt.Fprintln(stdout, "  - Defers:", GetColorForDefers(res.Defers, cfg), res.Defers, ColorReset)
+		fmt.Fprintln(stdout, "  - Panics:", GetColorForPanics(res), res.Panics, ColorReset)
+		fmt.Fprintln(stdout, "  - Recovers:", ColorGreen, res.Recovers, ColorReset)
+	}
+	fmt.Fprintln(stdout, "  - Comment Density (%):", fmt.Sprintf("%.1f", res.CommentDensity*100))
+	fmt.Fprintln(stdout, "  - Maintainability Index:", miColor, fmt.Sprintf("%.2f", res.MaintainabilityIndex), ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
 }
 
 // parseThresholdValues parses two threshold values from strings
@@ -353,14 +1014,77 @@ func updateThresholds(cfg *Config, metric string, value1, value2 float64) error
 	case "cyclomatic":
 		cfg.Cyclomatic.Medium = value1
 		cfg.Cyclomatic.High = value2
+	case "npath":
+		cfg.NPath.Medium = value1
+		cfg.NPath.High = value2
+	case "abc":
+		cfg.ABC.Medium = value1
+		cfg.ABC.High = value2
+	case "nesting":
+		cfg.Nesting.Medium = value1
+		cfg.Nesting.High = value2
 	case "maintainabilityIndex":
 		cfg.MaintainabilityIndex.Low = value1
 		cfg.MaintainabilityIndex.Medium = value2
 	case "loc":
 		cfg.LOC.Medium = value1
 		cfg.LOC.High = value2
+	case "lloc":
+		cfg.LLOC.Medium = value1
+		cfg.LLOC.High = value2
+	case "indentation":
+		cfg.Indentation.Medium = value1
+		cfg.Indentation.High = value2
+	case "localVars":
+		cfg.LocalVars.Medium = value1
+		cfg.LocalVars.High = value2
+	case "params":
+		cfg.Params.Medium = value1
+		cfg.Params.High = value2
+	case "results":
+		cfg.Results.Medium = value1
+		cfg.Results.High = value2
+	case "exits":
+		cfg.Exits.Medium = value1
+		cfg.Exits.High = value2
+	case "magicNumbers":
+		cfg.MagicNumbers.Medium = value1
+		cfg.MagicNumbers.High = value2
+	case "errorDensity":
+		cfg.ErrorDensity.Medium = value1
+		cfg.ErrorDensity.High = value2
+	case "fanOut":
+		cfg.FanOut.Medium = value1
+		cfg.FanOut.High = value2
+	case "concurrency":
+		cfg.Concurrency.Medium = value1
+		cfg.Concurrency.High = value2
+	case "distance":
+		cfg.Distance.Medium = value1
+		cfg.Distance.High = value2
+	case "lcom":
+		cfg.LCOM.Medium = value1
+		cfg.LCOM.High = value2
+	case "wmc":
+		cfg.WMC.Medium = value1
+		cfg.WMC.High = value2
+	case "structFields":
+		cfg.StructFields.Medium = value1
+		cfg.StructFields.High = value2
+	case "interfaceMethods":
+		cfg.InterfaceMethods.Medium = value1
+		cfg.InterfaceMethods.High = value2
+	case "goroutines":
+		cfg.Goroutines.Medium = value1
+		cfg.Goroutines.High = value2
+	case "defers":
+		cfg.Defers.Medium = value1
+		cfg.Defers.High = value2
+	case "thirdPartyImports":
+		cfg.ThirdPartyImports.Medium = value1
+		cfg.ThirdPartyImports.High = value2
 	default:
-		return fmt.Errorf("unknown metric '%s'. Valid metrics: cyclomatic, maintainabilityIndex, loc", metric)
+		return fmt.Errorf("unknown metric '%s'. Valid metrics: cyclomatic, npath, abc, nesting, maintainabilityIndex, loc, lloc, indentation, localVars, params, results, exits, magicNumbers, errorDensity, fanOut, concurrency, distance, lcom, wmc, structFields, interfaceMethods, goroutines, defers, thirdPartyImports", metric)
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// Default locations of the engine config and the analyzed code inside a Code Climate engine container
+const (
+	codeClimateConfigPath = "/config.json"
+	codeClimateCodeDir    = "/code"
+)
+
+// codeClimateEngineConfig is the part of the Code Climate engine config read by zeds. The
+// optional config section holds zeds configuration values, e.g. thresholds, over the defaults.
+type codeClimateEngineConfig struct {
+	IncludePaths []string        `json:"include_paths"`
+	Config       json.RawMessage `json:"config"`
+}
+
+// codeClimateIssue is an issue of the Code Climate engine specification
+type codeClimateIssue struct {
+	Type       string   `json:"type"`
+	Categories []string `json:"categories"`
+	codeQualityIssue
+}
+
+// handleCodeClimateCommand runs zeds as a Code Climate engine: it analyzes the include paths of
+// the engine config under the code directory and writes each threshold violation to stdout as an
+// issue terminated by a NUL character. Problems are reported on stderr, which the engine spec
+// reserves for diagnostics.
+func handleCodeClimateCommand(args []string) {
+	configFile, codeDir := codeClimateConfigPath, codeClimateCodeDir
+	flags := newFlagSet("codeclimate", "[options]")
+	flags.stringVar(&configFile, "config", "", "read the engine config from the `file`")
+	flags.stringVar(&codeDir, "code", "", "analyze the code in the `directory`")
+	positional, err := flags.parse(args[1:])
+	if err == nil && len(positional) > 0 {
+		err = fmt.Errorf("unexpected argument '%s'", positional[0])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(stderr, flags.usage())
+		exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error: "+err.Error())
+		fmt.Fprintln(stderr, flags.usage())
+		exit(exitError)
+	}
+
+	engine, err := readCodeClimateConfig(configFile)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error reading engine config: "+err.Error())
+		exit(exitError)
+	}
+	cfg := DefaultConfig()
+	if len(engine.Config) > 0 {
+		if err := json.Unmarshal(engine.Config, &cfg); err != nil {
+			fmt.Fprintln(stderr, "Error reading engine config: "+err.Error())
+			exit(exitError)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(stderr, "Error reading engine config: "+err.Error())
+		exit(exitError)
+	}
+	// Issue paths are reported relative to the working directory, which the spec expects to be the code directory.
+	if err := os.Chdir(codeDir); err != nil {
+		fmt.Fprintln(stderr, "Error opening code directory: "+err.Error())
+		exit(exitError)
+	}
+
+	files, err := codeClimateFiles(engine.IncludePaths)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error reading include paths: "+err.Error())
+		exit(exitError)
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Skipping "+path+": "+err.Error())
+			continue
+		}
+		// A file that does not parse is skipped so that one broken file does not fail the whole engine run.
+		results, _, err := analyzer.AnalyzeSource(path, src, cfg.AnalyzerOptions())
+		if err != nil {
+			fmt.Fprintln(stderr, "Skipping "+path+": "+err.Error())
+			continue
+		}
+		classifyResults(results, &cfg)
+		for _, v := range findViolations(path, results, &cfg) {
+			if err := writeCodeClimateIssue(enc, v); err != nil {
+				fmt.Fprintln(stderr, "Error writing issue: "+err.Error())
+				exit(exitError)
+			}
+		}
+	}
+}
+
+// readCodeClimateConfig reads the engine config. Without a config file, the whole code directory is analyzed.
+func readCodeClimateConfig(path string) (codeClimateEngineConfig, error) {
+	engine := codeClimateEngineConfig{IncludePaths: []string{"./"}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return engine, nil
+	}
+	if err != nil {
+		return engine, err
+	}
+	err = json.Unmarshal(data, &engine)
+	return engine, err
+}
+
+// codeClimateFiles expands the include paths, where directories end with a slash, into the Go files to analyze
+func codeClimateFiles(includePaths []string) ([]string, error) {
+	var files []string
+	for _, path := range includePaths {
+		if strings.HasSuffix(path, "/") {
+			dirFiles, err := collectGoFiles(path, true)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, dirFiles...)
+		} else if strings.HasSuffix(path, ".go") {
+			files = append(files, filepath.Clean(path))
+		}
+	}
+	return files, nil
+}
+
+// writeCodeClimateIssue writes a threshold violation as a Code Climate issue followed by the NUL separator
+func writeCodeClimateIssue(enc *json.Encoder, v violation) error {
+	issue := codeClimateIssue{
+		Type:             "issue",
+		Categories:       []string{"Complexity"},
+		codeQualityIssue: newCodeQualityIssue(v),
+	}
+	if err := enc.Encode(issue); err != nil {
+		return err
+	}
+	_, err := io.WriteString(stdout, "\x00")
+	return err
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// handleCompareCommand analyzes two files and prints their aggregate metrics side by side
+func handleCompareCommand(args []string) {
+	flags := newFlagSet("compare", "<old.go> <new.go>")
+	files := parseCommandArgs(flags, args[1:])
+	if len(files) != 2 {
+		failUsage(flags, fmt.Errorf("compare takes two files"))
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+
+	oldSummary := summarizeFile(files[0], cfg)
+	newSummary := summarizeFile(files[1], cfg)
+
+	printHeader()
+	oldName, newName := filepath.Base(files[0]), filepath.Base(files[1])
+	fmt.Fprintln(stdout, ColorCyan+"Comparison:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+	fmt.Fprintf(stdout, "%-18s %12s %12s %12s\n", "Metric", truncate(oldName, 12), truncate(newName, 12), "Delta")
+	printCompareRow("Functions", float64(oldSummary.Functions), float64(newSummary.Functions), 0, "")
+	printCompareRow("Total Complexity", float64(oldSummary.TotalCyclomatic), float64(newSummary.TotalCyclomatic), 0, "lower")
+	printCompareRow("Average MI", oldSummary.AverageMI, newSummary.AverageMI, 2, "higher")
+	printCompareRow("Total LOC", float64(oldSummary.TotalLOC), float64(newSummary.TotalLOC), 0, "lower")
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+}
+
+// summarizeFile analyzes a file with the configuration analyze uses for it and returns its
+// aggregate metrics, exiting on error
+func summarizeFile(path string, cfg *Config) analyzer.Summary {
+	cfg = cfg.ForFile(path)
+	fsys, name := diskFile(path)
+	results, _, err := analyzer.AnalyzeMethods(fsys, name, cfg.AnalyzerOptions())
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error analyzing "+path+": "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	return analyzer.Summarize(results)
+}
+
+// diskFile returns a file system rooted at the directory of a file on disk and the file's name within it
+func diskFile(path string) (fs.FS, string) {
+	return os.DirFS(filepath.Dir(path)), filepath.Base(path)
+}
+
+// printCompareRow prints one metric row. better is "lower" or "higher" to color the delta,
+// or empty when the change is neither an improvement nor a regression.
+func printCompareRow(label string, oldValue, newValue float64, precision int, better string) {
+	delta := newValue - oldValue
+	deltaText := fmt.Sprintf("%+.*f", precision, delta)
+	color := ""
+	if delta != 0 && better != "" {
+		if (delta < 0) == (better == "lower") {
+			color = ColorGreen
+		} else {
+			color = ColorRed
+		}
+	}
+	fmt.Fprintf(stdout, "%-18s %12.*f %12.*f %s%12s%s\n", label, precision, oldValue, precision, newValue, color, deltaText, ColorReset)
+}
+
+// truncate shortens a string to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
@@ -0,0 +1,558 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// Config defines the thresholds and settings for code analysis
+type Config struct {
+	Cyclomatic struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"cyclomatic"`
+	NPath struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"npath"`
+	ABC struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"abc"`
+	Nesting struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"nesting"`
+	MaintainabilityIndex struct {
+		Low    float64 `json:"low"`
+		Medium float64 `json:"medium"`
+	} `json:"maintainabilityIndex"`
+	LOC struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"loc"`
+	LLOC struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"lloc"`
+	Indentation struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"indentation"`
+	LocalVars struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"localVars"`
+	Params struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"params"`
+	Results struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"results"`
+	Exits struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"exits"`
+	MagicNumbers struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+		// Allowlist lists the numbers that are not counted as magic numbers.
+		Allowlist []float64 `json:"allowlist"`
+	} `json:"magicNumbers"`
+	// ErrorDensity thresholds apply to the share of a function's lines taken by error checks.
+	ErrorDensity struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"errorDensity"`
+	FanOut struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"fanOut"`
+	// Distance thresholds apply to the distance of packages from the main sequence, see `zeds packages`.
+	Distance struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"distance"`
+	// DocCoverage sets the minimum percentage of exported identifiers with doc comments, per package.
+	DocCoverage struct {
+		Minimum float64 `json:"minimum"`
+	} `json:"docCoverage"`
+	// LCOM, WMC, StructFields and InterfaceMethods thresholds apply to types rather than functions.
+	LCOM struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"lcom"`
+	WMC struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"wmc"`
+	StructFields struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"structFields"`
+	InterfaceMethods struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"interfaceMethods"`
+	Concurrency struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"concurrency"`
+	// Goroutines and Defers thresholds are optional; a zero high threshold disables coloring.
+	Goroutines struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"goroutines"`
+	Defers struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"defers"`
+	// ThirdPartyImports thresholds apply to the third-party imports of a file or package, and are optional.
+	ThirdPartyImports struct {
+		Medium float64 `json:"medium"`
+		High   float64 `json:"high"`
+	} `json:"thirdPartyImports"`
+	// TechnicalDebt configures the remediation time estimated for threshold violations.
+	TechnicalDebt struct {
+		// MinutesPerUnit maps a metric to the minutes it takes to remove one unit beyond its medium threshold.
+		MinutesPerUnit map[string]float64 `json:"minutesPerUnit"`
+		// MinutesPerLine is the estimated time to develop a line of code, the base of the debt ratio.
+		MinutesPerLine float64 `json:"minutesPerLine"`
+	} `json:"technicalDebt"`
+	CommentDensityMultiplier float64 `json:"commentDensityMultiplier"`
+	// DebtMarkers lists the words that mark technical debt notes in comments.
+	DebtMarkers []string `json:"debtMarkers"`
+	// HalsteadProfile selects the operator classification used for Halstead Volume.
+	HalsteadProfile analyzer.HalsteadProfile `json:"halsteadProfile"`
+	// HalsteadMeasures lists the derived Halstead measures shown next to the volume, see halsteadMeasures.
+	HalsteadMeasures []string `json:"halsteadMeasures"`
+	// MIVariant selects the Maintainability Index formula.
+	MIVariant analyzer.MIVariant `json:"miVariant"`
+	// FunctionLength selects the length metric, "loc" or "lloc", that the violations, severity,
+	// score and god function flag of a function are based on.
+	FunctionLength string `json:"functionLength"`
+	// TabWidth is the display width of a tab, used by the indentation metric and the longLines lint check.
+	TabWidth int `json:"tabWidth"`
+	// ScoreGrades maps quality score ranges to letter grades.
+	ScoreGrades []ScoreGrade `json:"scoreGrades"`
+	// UniformMetrics configures the warning for files whose functions all report nearly identical metrics.
+	UniformMetrics struct {
+		MinFunctions int     `json:"minFunctions"`
+		MaxVariance  float64 `json:"maxVariance"`
+	} `json:"uniformMetrics"`
+	// GodFunction flags functions that exceed several thresholds at once.
+	GodFunction GodFunctionRule `json:"godFunction"`
+	// QualityGate lists conditions on the whole analysis that `analyze` checks after reporting.
+	QualityGate QualityGate `json:"qualityGate"`
+	// Theme selects the colors of the terminal output.
+	Theme Theme `json:"theme"`
+	// Lint enables or disables the advisory checks reported by `analyze --lint`.
+	Lint struct {
+		ErrorAndPanic bool `json:"errorAndPanic"`
+		IgnoredErrors bool `json:"ignoredErrors"`
+		// ComplexInit flags init functions whose cyclomatic complexity reaches InitComplexity.
+		ComplexInit    bool `json:"complexInit"`
+		InitComplexity int  `json:"initComplexity"`
+		// LongFile flags files whose lines or functions reach MaxFileLines or MaxFileFunctions.
+		LongFile         bool `json:"longFile"`
+		MaxFileLines     int  `json:"maxFileLines"`
+		MaxFileFunctions int  `json:"maxFileFunctions"`
+		// LongLines flags lines wider than MaxLineWidth columns, with tabs expanded to TabWidth.
+		LongLines    bool `json:"longLines"`
+		MaxLineWidth int  `json:"maxLineWidth"`
+	} `json:"lint"`
+	// Overrides replace values of the configuration for the files matching their paths.
+	Overrides []PathOverride `json:"overrides"`
+	// Profiles hold named sets of values, of which --profile selects one to apply over the others.
+	Profiles map[string]json.RawMessage `json:"profiles"`
+
+	// fileConfigs caches the configurations of ForFile by the overrides they apply
+	fileConfigs map[string]*Config
+}
+
+// Function length metrics
+const (
+	FunctionLengthLOC  = "loc"
+	FunctionLengthLLOC = "lloc"
+)
+
+// God function rule modes: "all" requires high cyclomatic complexity, high LOC and low MI,
+// "twoOfThree" requires any two of them.
+const (
+	GodFunctionAll        = "all"
+	GodFunctionTwoOfThree = "twoOfThree"
+)
+
+// GodFunctionRule configures the composite "god function" flag.
+type GodFunctionRule struct {
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode"`
+}
+
+// QualityGate configures the conditions an analysis must meet to pass. A condition set to 0 is not checked.
+type QualityGate struct {
+	Enabled bool `json:"enabled"`
+	// MaxCyclomatic is the highest cyclomatic complexity allowed for any function.
+	MaxCyclomatic int `json:"maxCyclomatic"`
+	// MinAverageMI is the lowest average maintainability index allowed over all functions.
+	MinAverageMI float64 `json:"minAverageMI"`
+	// MaxHighViolationsPerPackage is the most high threshold violations allowed in a package.
+	MaxHighViolationsPerPackage int `json:"maxHighViolationsPerPackage"`
+}
+
+// ScoreGrade labels the quality scores in the half-open range [Min, Max).
+// The highest band also includes its Max.
+type ScoreGrade struct {
+	Label string  `json:"label"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// configPath is the configuration file in use: the --config file, or the project configuration
+// file found by locateConfig
+var configPath = filepath.Join(".", "config.json")
+
+// configFromFlag is set when --config or ZEDS_CONFIG names the configuration file, which turns off discovery
+var configFromFlag bool
+
+// configFileNames lists the names of the project configuration files, in order of precedence
+var configFileNames = []string{"zeds.json", "zeds.yaml", "zeds.yml", "zeds.toml", ".zeds.json", ".zeds.yaml", ".zeds.yml", ".zeds.toml"}
+
+// extractConfigFlags removes the global --config and --profile flags from the arguments. --config,
+// or else ZEDS_CONFIG, names the configuration file, and --profile, or else ZEDS_PROFILE, selects a
+// profile of it. The codeclimate command keeps its own --config, which names the engine config.
+func extractConfigFlags(args []string) ([]string, error) {
+	if len(args) > 0 && args[0] == "codeclimate" {
+		return args, nil
+	}
+	if path := os.Getenv(envName("config")); path != "" {
+		configPath, configFromFlag = path, true
+	}
+	configProfile = os.Getenv(envName("profile"))
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		var name, value string
+		var err error
+		switch {
+		case args[i] == "--config" || args[i] == "--profile":
+			name = args[i]
+			value, err = nextArg(args, &i)
+		case strings.HasPrefix(args[i], "--config=") || strings.HasPrefix(args[i], "--profile="):
+			name, value, _ = strings.Cut(args[i], "=")
+		default:
+			rest = append(rest, args[i])
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			return nil, fmt.Errorf("missing value for %s", name)
+		}
+		if name == "--config" {
+			configPath, configFromFlag = value, true
+		} else {
+			configProfile = value
+		}
+	}
+	return rest, nil
+}
+
+// locateConfig makes the project configuration file that applies to path the configuration file:
+// the first of configFileNames found in the directory of path or one of its parents, up to the root
+// of the git repository. Without one, the config.json of the current directory is used. The file
+// given with --config is kept.
+func locateConfig(path string) {
+	if configFromFlag {
+		return
+	}
+	configPath = filepath.Join(".", "config.json")
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		for _, name := range configFileNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				configPath = candidate
+				return
+			}
+		}
+		// The root of the repository ends the search, so that no configuration of an enclosing
+		// directory applies.
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// DefaultConfig returns the built-in configuration values.
+func DefaultConfig() Config {
+	cfg := Config{
+		CommentDensityMultiplier: 5,
+		HalsteadProfile:          analyzer.HalsteadClassic,
+		HalsteadMeasures:         []string{},
+		MIVariant:                analyzer.MIZeds,
+		TabWidth:                 analyzer.DefaultTabWidth,
+		ScoreGrades: []ScoreGrade{
+			{Label: "A", Min: 90, Max: 100},
+			{Label: "B", Min: 80, Max: 90},
+			{Label: "C", Min: 70, Max: 80},
+			{Label: "D", Min: 60, Max: 70},
+			{Label: "F", Min: 0, Max: 60},
+		},
+	}
+	cfg.Cyclomatic.Medium = 6
+	cfg.Cyclomatic.High = 10
+	cfg.NPath.Medium = 200
+	cfg.NPath.High = 1000
+	cfg.ABC.Medium = 17
+	cfg.ABC.High = 30
+	cfg.Nesting.Medium = 4
+	cfg.Nesting.High = 6
+	cfg.MaintainabilityIndex.Low = 40
+	cfg.MaintainabilityIndex.Medium = 60
+	cfg.LOC.Medium = 20
+	cfg.LOC.High = 40
+	cfg.LLOC.Medium = 15
+	cfg.LLOC.High = 30
+	cfg.Indentation.Medium = 50
+	cfg.Indentation.High = 100
+	cfg.FunctionLength = FunctionLengthLOC
+	cfg.LocalVars.Medium = 8
+	cfg.LocalVars.High = 15
+	cfg.Params.Medium = 5
+	cfg.Params.High = 7
+	cfg.Results.Medium = 4
+	cfg.Results.High = 5
+	cfg.Exits.Medium = 6
+	cfg.Exits.High = 10
+	cfg.MagicNumbers.Medium = 3
+	cfg.MagicNumbers.High = 6
+	cfg.MagicNumbers.Allowlist = append([]float64(nil), analyzer.DefaultMagicNumberAllowlist...)
+	cfg.DebtMarkers = append([]string(nil), analyzer.DefaultDebtMarkers...)
+	cfg.ErrorDensity.Medium = 0.5
+	cfg.ErrorDensity.High = 0.7
+	cfg.FanOut.Medium = 8
+	cfg.FanOut.High = 15
+	cfg.Concurrency.Medium = 5
+	cfg.Concurrency.High = 10
+	cfg.Distance.Medium = 0.4
+	cfg.Distance.High = 0.7
+	cfg.DocCoverage.Minimum = 80
+	cfg.LCOM.Medium = 0.8
+	cfg.LCOM.High = 1
+	cfg.WMC.Medium = 30
+	cfg.WMC.High = 50
+	cfg.StructFields.Medium = 15
+	cfg.StructFields.High = 25
+	cfg.InterfaceMethods.Medium = 5
+	cfg.InterfaceMethods.High = 10
+	cfg.TechnicalDebt.MinutesPerUnit = map[string]float64{
+		"cyclomatic": 10, "npath": 0, "abc": 2, "nesting": 15, "maintainabilityIndex": 2, "loc": 1,
+		"lloc": 0, "indentation": 0, "localVars": 3, "params": 10, "results": 10, "exits": 5, "magicNumbers": 2, "errorDensity": 60,
+		"fanOut": 5, "concurrency": 10, "goroutines": 10, "defers": 5,
+	}
+	cfg.TechnicalDebt.MinutesPerLine = 30
+	cfg.UniformMetrics.MinFunctions = 10
+	cfg.UniformMetrics.MaxVariance = 0.5
+	cfg.GodFunction = GodFunctionRule{Enabled: true, Mode: GodFunctionAll}
+	cfg.QualityGate = QualityGate{MaxCyclomatic: 15, MinAverageMI: 55, MaxHighViolationsPerPackage: 3}
+	cfg.Theme = Theme{Name: ThemeDark, Colors: map[string]string{}}
+	cfg.Lint.ErrorAndPanic = true
+	cfg.Lint.IgnoredErrors = true
+	cfg.Lint.ComplexInit = true
+	cfg.Lint.InitComplexity = 5
+	cfg.Lint.LongFile = true
+	cfg.Lint.MaxFileLines = 1000
+	cfg.Lint.MaxFileFunctions = 40
+	cfg.Lint.MaxLineWidth = 120
+	cfg.Overrides = []PathOverride{}
+	cfg.Profiles = map[string]json.RawMessage{}
+	return cfg
+}
+
+// LoadConfig reads the configuration file and applies the selected profile and then the ZEDS_
+// environment variables over it. If the file does not exist, the default values are used without
+// creating the file; `zeds init` creates one on request.
+func LoadConfig() (*Config, error) {
+	cfg, _, err := loadConfigSteps()
+	return cfg, err
+}
+
+// Steps of LoadConfig, named in configSteps.failed
+const (
+	configStepFile        = "file"
+	configStepProfile     = "profile"
+	configStepEnvironment = "environment"
+)
+
+// configSteps records what LoadConfig applied, so that doctor reports exactly the configuration
+// that analyze uses
+type configSteps struct {
+	// path is the configuration file read, empty when there is none and the defaults apply
+	path string
+	// profile is the profile applied, empty when there is none
+	profile string
+	// environment lists the ZEDS_ variables applied
+	environment []string
+	// failed names the step that failed, if any
+	failed string
+}
+
+// loadConfigSteps loads the configuration like LoadConfig and returns the steps it applied
+func loadConfigSteps() (*Config, configSteps, error) {
+	var steps configSteps
+	cfg, err := loadConfigFile()
+	if err != nil {
+		steps.failed = configStepFile
+		return nil, steps, err
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		steps.path = configPath
+	}
+	if steps.profile, err = cfg.applyProfile(configProfile); err != nil {
+		steps.failed = configStepProfile
+		return nil, steps, err
+	}
+	if steps.environment, err = applyConfigEnvironment(cfg); err != nil {
+		steps.failed = configStepEnvironment
+		return nil, steps, err
+	}
+	return cfg, steps, nil
+}
+
+// loadConfigFile reads the configuration file, or returns the default values if it does not exist
+func loadConfigFile() (*Config, error) {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		cfg := DefaultConfig()
+		return &cfg, nil
+	}
+	return readConfig(configPath)
+}
+
+// readConfig parses the configuration file at the given path without creating it.
+func readConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = configToJSON(data, configFormat(path)); err != nil {
+		return nil, err
+	}
+	// Start from the defaults so that keys missing from the file keep their default values.
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that the configuration values are usable.
+func (c *Config) Validate() error {
+	if c.TabWidth <= 0 {
+		return fmt.Errorf("tabWidth must be positive, got %d", c.TabWidth)
+	}
+	if !c.HalsteadProfile.IsValid() {
+		return fmt.Errorf("unknown halsteadProfile %q. Valid profiles: %v", c.HalsteadProfile, analyzer.HalsteadProfiles)
+	}
+	for _, key := range c.HalsteadMeasures {
+		if _, ok := findHalsteadMeasure(key); !ok {
+			return fmt.Errorf("unknown halsteadMeasures entry %q. Valid measures: %s", key, halsteadMeasureKeys())
+		}
+	}
+	if !c.MIVariant.IsValid() {
+		return fmt.Errorf("unknown miVariant %q. Valid variants: %v", c.MIVariant, analyzer.MIVariants)
+	}
+	if c.DocCoverage.Minimum < 0 || c.DocCoverage.Minimum > 100 {
+		return fmt.Errorf("docCoverage.minimum must be between 0 and 100, got %v", c.DocCoverage.Minimum)
+	}
+	if c.FunctionLength != FunctionLengthLOC && c.FunctionLength != FunctionLengthLLOC {
+		return fmt.Errorf("functionLength must be %q or %q, got %q", FunctionLengthLOC, FunctionLengthLLOC, c.FunctionLength)
+	}
+	if err := c.validateTechnicalDebt(); err != nil {
+		return err
+	}
+	if c.GodFunction.Mode != GodFunctionAll && c.GodFunction.Mode != GodFunctionTwoOfThree {
+		return fmt.Errorf("godFunction.mode must be %q or %q, got %q", GodFunctionAll, GodFunctionTwoOfThree, c.GodFunction.Mode)
+	}
+	if g := c.QualityGate; g.MaxCyclomatic < 0 || g.MinAverageMI < 0 || g.MaxHighViolationsPerPackage < 0 {
+		return fmt.Errorf("qualityGate conditions must not be negative")
+	}
+	if err := c.Theme.Validate(); err != nil {
+		return err
+	}
+	if err := validateScoreGrades(c.ScoreGrades); err != nil {
+		return err
+	}
+	if err := c.validateOverrides(); err != nil {
+		return err
+	}
+	return c.validateProfiles()
+}
+
+// validateTechnicalDebt checks that the remediation times name known metrics and are not negative
+func (c *Config) validateTechnicalDebt() error {
+	if c.TechnicalDebt.MinutesPerLine <= 0 {
+		return fmt.Errorf("technicalDebt.minutesPerLine must be positive, got %v", c.TechnicalDebt.MinutesPerLine)
+	}
+	for key, minutes := range c.TechnicalDebt.MinutesPerUnit {
+		if _, ok := findThresholdMetric(key); !ok {
+			return fmt.Errorf("technicalDebt.minutesPerUnit: unknown metric %q", key)
+		}
+		if minutes < 0 {
+			return fmt.Errorf("technicalDebt.minutesPerUnit: %s must not be negative, got %v", key, minutes)
+		}
+	}
+	return nil
+}
+
+// validateScoreGrades checks that the grade ranges are well-formed, contiguous and non-overlapping.
+func validateScoreGrades(grades []ScoreGrade) error {
+	sorted := sortedGrades(grades)
+	for i, g := range sorted {
+		if g.Label == "" {
+			return fmt.Errorf("scoreGrades: every grade needs a label")
+		}
+		if g.Min >= g.Max {
+			return fmt.Errorf("scoreGrades: grade %s has an empty range %v-%v", g.Label, g.Min, g.Max)
+		}
+		if i > 0 && sorted[i-1].Min != g.Max {
+			if g.Max > sorted[i-1].Min {
+				return fmt.Errorf("scoreGrades: grades %s and %s overlap", sorted[i-1].Label, g.Label)
+			}
+			return fmt.Errorf("scoreGrades: gap between grades %s and %s", sorted[i-1].Label, g.Label)
+		}
+	}
+	return nil
+}
+
+// AnalyzerOptions returns the analyzer options derived from the configuration.
+func (c *Config) AnalyzerOptions() analyzer.Options {
+	return analyzer.Options{
+		CommentDensityMultiplier: c.CommentDensityMultiplier,
+		HalsteadProfile:          c.HalsteadProfile,
+		MIVariant:                c.MIVariant,
+		MagicNumberAllowlist:     c.MagicNumbers.Allowlist,
+		TabWidth:                 c.TabWidth,
+	}
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rawMessageType is the type of configuration values kept as JSON, such as the values of a profile
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// handleConfigCommand reads and updates the configuration by dotted keys, e.g. cyclomatic.high
+func handleConfigCommand(args []string) {
+	flags := newFlagSet("config", "get <key>", "set <key> <value>", "list")
+	positional := parseCommandArgs(flags, args[1:])
+	if len(positional) == 0 {
+		failUsage(flags, fmt.Errorf("expected get, set or list"))
+	}
+	switch action, operands := positional[0], positional[1:]; {
+	case action == "get" && len(operands) == 1:
+		configGet(operands[0])
+	case action == "set" && len(operands) == 2:
+		configSet(operands[0], operands[1])
+	case action == "list" && len(operands) == 0:
+		configList()
+	case action == "get" || action == "set" || action == "list":
+		failUsage(flags, fmt.Errorf("wrong number of arguments for %s", action))
+	default:
+		failUsage(flags, fmt.Errorf("unknown action '%s'. Valid actions: get, set, list", action))
+	}
+}
+
+// configGet prints the effective value of a key: a scalar as is, anything else as JSON
+func configGet(key string) {
+	if _, err := configKeyType(key); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	values, err := configValues(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error encoding config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	var value interface{} = values
+	for _, part := range strings.Split(key, ".") {
+		object, _ := value.(map[string]interface{})
+		if value = object[part]; value == nil {
+			fmt.Fprintln(stderr, ColorRed+"Error: "+key+" is not set"+ColorReset)
+			exit(exitError)
+		}
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if err := writeJSON(value); err != nil {
+			fmt.Fprintln(stderr, "Error writing value: "+err.Error())
+			exit(exitError)
+		}
+	default:
+		fmt.Fprintln(stdout, value)
+	}
+}
+
+// configSet updates a key in the configuration file, or in the profile selected with --profile,
+// creating the file if needed. Only that key of the file changes, so its comments are kept.
+func configSet(key, setting string) {
+	t, err := configKeyType(key)
+	if err == nil && strings.HasPrefix(key, "profiles.") && configProfile != "" {
+		err = fmt.Errorf("profiles cannot be set with --profile")
+	}
+	var value interface{}
+	if err == nil {
+		value, err = parseConfigValue(t, setting)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	path := strings.Split(key, ".")
+	if configProfile != "" {
+		// The profile holds only the keys set in it.
+		path = append([]string{"profiles", configProfile}, path...)
+	}
+	if err := updateConfig(configUpdate{path, value}); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	target := configPath
+	if configProfile != "" {
+		target += " (profile " + configProfile + ")"
+	}
+	fmt.Fprintln(stdout, ColorGreen+"Set "+key+" to "+setting+" in "+target+"."+ColorReset)
+}
+
+// configList prints every effective configuration value as a key = value line, in the order of
+// the configuration file
+func configList() {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	listConfigValues("", reflect.ValueOf(*cfg))
+}
+
+// listConfigValues prints the leaves of a configuration value under the key prefix. Lists and
+// values kept as JSON are printed as JSON on one line.
+func listConfigValues(prefix string, v reflect.Value) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if name := jsonName(t.Field(i)); name != "" {
+				listConfigValues(joinKey(prefix, name), v.Field(i))
+			}
+		}
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			listConfigValues(joinKey(prefix, key.String()), v.MapIndex(key))
+		}
+	case v.Kind() == reflect.Slice:
+		data, _ := json.Marshal(v.Interface())
+		fmt.Fprintln(stdout, ColorCyan+prefix+ColorReset+" = "+string(data))
+	default:
+		fmt.Fprintln(stdout, ColorCyan+prefix+ColorReset+" = "+fmt.Sprint(v.Interface()))
+	}
+}
+
+// joinKey appends a name to a dotted key
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// jsonName returns the name of a struct field in the configuration file, or "" for fields that
+// are not part of it
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// configKeyType returns the type of the value at a dotted key of the configuration. Struct fields
+// are named by their key in the configuration file, while maps, such as the colors of a theme,
+// accept any key.
+func configKeyType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		switch {
+		case t.Kind() == reflect.Struct:
+			var names []string
+			var field *reflect.StructField
+			for j := 0; j < t.NumField(); j++ {
+				f := t.Field(j)
+				if name := jsonName(f); name != "" {
+					names = append(names, name)
+					if name == part {
+						field = &f
+					}
+				}
+			}
+			if field == nil {
+				prefix := strings.Join(parts[:i], ".")
+				if prefix == "" {
+					return nil, fmt.Errorf("unknown key '%s'. Valid keys: %s", part, strings.Join(names, ", "))
+				}
+				return nil, fmt.Errorf("unknown key '%s' in %s. Valid keys: %s", part, prefix, strings.Join(names, ", "))
+			}
+			t = field.Type
+		case t.Kind() == reflect.Map && part != "":
+			t = t.Elem()
+		case t == rawMessageType && parts[0] == "profiles":
+			return nil, fmt.Errorf("%s is set as a whole; set the keys of a profile with --profile", strings.Join(parts[:i], "."))
+		default:
+			return nil, fmt.Errorf("%s has no key '%s'", strings.Join(parts[:i], "."), part)
+		}
+	}
+	return t, nil
+}
+
+// parseConfigValue parses the value of a key of the given type. Numbers, booleans and strings are
+// written as is, lists of them are separated by commas, and other values are written as JSON.
+func parseConfigValue(t reflect.Type, setting string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Float64, reflect.Int:
+		n, err := strconv.ParseFloat(setting, 64)
+		if err != nil || t.Kind() == reflect.Int && n != float64(int(n)) {
+			return nil, fmt.Errorf("expected a number, got %q", setting)
+		}
+		return n, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(setting)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", setting)
+		}
+		return b, nil
+	case reflect.String:
+		return setting, nil
+	case reflect.Slice:
+		if elem := t.Elem().Kind(); t != rawMessageType && (elem == reflect.String || elem == reflect.Float64) && !strings.HasPrefix(setting, "[") {
+			list := []interface{}{}
+			for _, item := range strings.Split(setting, ",") {
+				if item = strings.TrimSpace(item); item == "" {
+					continue
+				}
+				value, err := parseConfigValue(t.Elem(), item)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			return list, nil
+		}
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(setting), &value); err != nil {
+		return nil, fmt.Errorf("expected a JSON value, got %q", setting)
+	}
+	return value, nil
+}
+
+// configValues returns the configuration decoded into generic JSON values
+func configValues(cfg *Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	err = json.Unmarshal(data, &values)
+	return values, err
+}
+
+// setConfigValue sets the value at a key path of generic JSON values, creating missing objects
+func setConfigValue(values map[string]interface{}, path []string, value interface{}) {
+	for _, part := range path[:len(path)-1] {
+		child, ok := values[part].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			values[part] = child
+		}
+		values = child
+	}
+	values[path[len(path)-1]] = value
+}
@@ -0,0 +1,739 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configUpdate is a value to set at a key path of the configuration file
+type configUpdate struct {
+	path  []string
+	value interface{}
+}
+
+// updateConfig sets values in the configuration file after validating the configuration they
+// result in. Only the keys set are edited, so the rest of the file, comments included, is kept;
+// a missing file is created with just those keys.
+func updateConfig(updates ...configUpdate) error {
+	// Without a configuration file, the file is created under the name `zeds init` uses, so that
+	// a file written by init later cannot take precedence over it unnoticed.
+	if _, err := os.Stat(configPath); os.IsNotExist(err) && !configFromFlag {
+		configPath = initFileNames[configYAML]
+	}
+	// Values from the environment and profiles must not be validated as part of the file.
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return fmt.Errorf("loading %s: %v", configPath, err)
+	}
+	values, err := configValues(cfg)
+	if err != nil {
+		return err
+	}
+	for _, update := range updates {
+		setConfigValue(values, update.path, update.value)
+	}
+	updated := DefaultConfig()
+	data, err := json.Marshal(values)
+	if err == nil {
+		err = json.Unmarshal(data, &updated)
+	}
+	if err == nil {
+		err = updated.Validate()
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err = os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	format := configFormat(configPath)
+	for _, update := range updates {
+		if data, err = editConfig(data, format, update.path, update.value); err != nil {
+			return fmt.Errorf("cannot set %s in %s: %v", strings.Join(update.path, "."), configPath, err)
+		}
+	}
+	return os.WriteFile(configPath, data, 0644)
+}
+
+// editConfig sets the value at a key path of a configuration file in the format, and checks
+// that nothing else changed
+func editConfig(data []byte, format string, path []string, value interface{}) ([]byte, error) {
+	var edited []byte
+	var err error
+	switch format {
+	case configYAML:
+		edited, err = editYAML(data, path, value)
+	case configTOML:
+		edited, err = editTOML(data, path, value)
+	default:
+		edited, err = editJSON(data, path, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := decodeConfigValues(data, format)
+	if err != nil {
+		return nil, err
+	}
+	setConfigValue(want, path, value)
+	got, err := decodeConfigValues(edited, format)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		return nil, fmt.Errorf("the file is laid out in a way that cannot be edited in place; edit it by hand")
+	}
+	return edited, nil
+}
+
+// decodeConfigValues decodes a configuration file into generic JSON values
+func decodeConfigValues(data []byte, format string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return values, nil
+	}
+	data, err := configToJSON(data, format)
+	if err == nil {
+		err = json.Unmarshal(data, &values)
+	}
+	return values, err
+}
+
+// editYAML sets the value at a key path of a YAML file through its node tree, which keeps the
+// comments and the order of the keys
+func editYAML(data []byte, path []string, value interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	node, err := valueNode(value)
+	if err != nil {
+		return nil, err
+	}
+	blockStyle(node)
+	if err := setNode(doc.Content[0], path, node); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return restoreBlankLines(data, buf.Bytes()), nil
+}
+
+// restoreBlankLines puts back the blank lines of a YAML file that the encoder drops, before the
+// same lines of the edited file as in the original
+func restoreBlankLines(original, edited []byte) []byte {
+	lines := strings.Split(string(original), "\n")
+	out := strings.Split(string(edited), "\n")
+	var result []string
+	next := 0
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			continue
+		}
+		blank := i
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+		if i == len(lines) {
+			break
+		}
+		for j := next; j < len(out); j++ {
+			if out[j] == lines[i] {
+				result = append(result, out[next:j]...)
+				// The encoder keeps the blank line after the comment at the top of a file.
+				for k := len(result) - 1; k >= 0 && result[k] == "" && blank < i; k-- {
+					blank++
+				}
+				for ; blank < i; blank++ {
+					result = append(result, "")
+				}
+				next = j
+				break
+			}
+		}
+	}
+	result = append(result, out[next:]...)
+	return []byte(strings.Join(result, "\n"))
+}
+
+// editJSON sets the value at a key path of a JSON file, keeping the order of its keys
+func editJSON(data []byte, path []string, value interface{}) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	if len(bytes.TrimSpace(data)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var err error
+		if root, err = jsonNode(dec); err != nil {
+			return nil, err
+		}
+	}
+	node, err := valueNode(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := setNode(root, path, node); err != nil {
+		return nil, err
+	}
+	var compact, buf bytes.Buffer
+	writeNodeJSON(&compact, root)
+	if err := json.Indent(&buf, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// valueNode returns the YAML node of a generic JSON value
+func valueNode(value interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Content[0], nil
+}
+
+// setNode sets the value at a key path of a mapping node, creating the missing mappings. The
+// comments of a replaced value are moved to the new one.
+func setNode(mapping *yaml.Node, path []string, value *yaml.Node) error {
+	for i, key := range path {
+		if mapping.Kind != yaml.MappingNode && i == 0 {
+			return fmt.Errorf("the file does not hold an object")
+		}
+		if mapping.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not an object", strings.Join(path[:i], "."))
+		}
+		if len(mapping.Content) == 0 {
+			// An empty mapping is written as {}, and one that gains keys in block style.
+			mapping.Style &^= yaml.FlowStyle
+		}
+		index := -1
+		for j := 0; j+1 < len(mapping.Content); j += 2 {
+			if mapping.Content[j].Value == key {
+				index = j + 1
+			}
+		}
+		if index < 0 {
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			mapping.Content = append(mapping.Content, keyNode, &yaml.Node{Kind: yaml.MappingNode})
+			index = len(mapping.Content) - 1
+		}
+		child := mapping.Content[index]
+		if i == len(path)-1 {
+			value.HeadComment, value.LineComment, value.FootComment = child.HeadComment, child.LineComment, child.FootComment
+			mapping.Content[index] = value
+			return nil
+		}
+		if child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+			child = &yaml.Node{Kind: yaml.MappingNode, HeadComment: child.HeadComment, LineComment: child.LineComment}
+			mapping.Content[index] = child
+		}
+		mapping = child
+	}
+	return nil
+}
+
+// jsonNode decodes the next JSON value into a YAML node, which keeps the order of object keys
+func jsonNode(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if t == '{' {
+			node.Kind = yaml.MappingNode
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := jsonNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		// The closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+// writeNodeJSON writes a node decoded from JSON, or from a generic JSON value, as compact JSON
+func writeNodeJSON(buf *bytes.Buffer, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		open, end := "[", "]"
+		if node.Kind == yaml.MappingNode {
+			open, end = "{", "}"
+		}
+		buf.WriteString(open)
+		for i, child := range node.Content {
+			switch {
+			case i > 0 && node.Kind == yaml.MappingNode && i%2 == 1:
+				buf.WriteString(":")
+			case i > 0:
+				buf.WriteString(",")
+			}
+			writeNodeJSON(buf, child)
+		}
+		buf.WriteString(end)
+	default:
+		if node.Tag == "!!str" {
+			data, _ := json.Marshal(node.Value)
+			buf.Write(data)
+		} else {
+			buf.WriteString(node.Value)
+		}
+	}
+}
+
+// tomlHeader matches the header of a TOML table, [a.b], or of an array of tables, [[a]]
+var tomlHeader = regexp.MustCompile(`^\s*(\[\[?)\s*(.+?)\s*\]\]?\s*(?:#.*)?$`)
+
+// tomlKeyValue matches the key of a TOML key/value line up to the start of the value
+var tomlKeyValue = regexp.MustCompile(`^\s*((?:[A-Za-z0-9_-]+|"(?:[^"\\]|\\.)*"|'[^']*')(?:\s*\.\s*(?:[A-Za-z0-9_-]+|"(?:[^"\\]|\\.)*"|'[^']*'))*)\s*=\s*`)
+
+// tomlSection is a table of a TOML file: the lines from its header to the next header
+type tomlSection struct {
+	path  []string
+	array bool
+	start int
+	end   int
+}
+
+// tomlEntry is a key/value of a TOML file, whose value ends at column endCol of line endLine
+type tomlEntry struct {
+	section int
+	key     []string
+	line    int
+	col     int
+	endLine int
+	endCol  int
+}
+
+// editTOML sets the value at a key path of a TOML file by editing its lines. The TOML library has
+// no syntax tree to edit, so the value of an existing key is replaced in place, a new key is
+// added to its table, and a top-level table is rewritten as a whole.
+func editTOML(data []byte, path []string, value interface{}) ([]byte, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	sections, entries, err := parseTOMLLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		section := sections[entry.section]
+		if section.array {
+			continue
+		}
+		key := append(append([]string{}, section.path...), entry.key...)
+		if len(key) < len(path) && equalKeys(key, path[:len(key)]) {
+			// TOML does not allow adding keys to an inline table.
+			return nil, fmt.Errorf("%s is an inline value of the file; set it as a whole", strings.Join(key, "."))
+		}
+		if equalKeys(key, path) {
+			inline, err := tomlValue(value)
+			if err != nil {
+				return nil, err
+			}
+			replaced := lines[entry.line][:entry.col] + inline + lines[entry.endLine][entry.endCol:]
+			return joinLines(lines[:entry.line], []string{replaced}, lines[entry.endLine+1:]), nil
+		}
+	}
+
+	var tables []tomlSection
+	for _, section := range sections[1:] {
+		if len(section.path) >= len(path) && equalKeys(section.path[:len(path)], path) {
+			tables = append(tables, section)
+		}
+	}
+	if len(tables) > 0 {
+		if len(path) > 1 {
+			return nil, fmt.Errorf("%s is a table of the file; set its keys one by one", strings.Join(path, "."))
+		}
+		return replaceTOMLTables(lines, tables, path[0], value)
+	}
+	return addTOMLKey(lines, sections, entries, path, value)
+}
+
+// parseTOMLLines splits the lines of a TOML file into its sections, the first one being the
+// top-level table, and its key/values
+func parseTOMLLines(lines []string) ([]tomlSection, []tomlEntry, error) {
+	sections := []tomlSection{{start: -1, end: len(lines)}}
+	var entries []tomlEntry
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		if trimmed[0] == '[' {
+			m := tomlHeader.FindStringSubmatch(lines[i])
+			if m == nil {
+				return nil, nil, fmt.Errorf("cannot read line %d", i+1)
+			}
+			sections[len(sections)-1].end = i
+			sections = append(sections, tomlSection{path: splitTOMLKey(m[2]), array: m[1] == "[[", start: i, end: len(lines)})
+			continue
+		}
+		m := tomlKeyValue.FindStringSubmatchIndex(lines[i])
+		if m == nil {
+			return nil, nil, fmt.Errorf("cannot read line %d", i+1)
+		}
+		endLine, endCol := tomlValueEnd(lines, i, m[1])
+		entries = append(entries, tomlEntry{
+			section: len(sections) - 1,
+			key:     splitTOMLKey(lines[i][m[2]:m[3]]),
+			line:    i,
+			col:     m[1],
+			endLine: endLine,
+			endCol:  endCol,
+		})
+		i = endLine
+	}
+	return sections, entries, nil
+}
+
+// tomlValueEnd returns where the TOML value starting at column col of line i ends, skipping
+// strings and following arrays and inline tables over several lines
+func tomlValueEnd(lines []string, i, col int) (int, int) {
+	depth := 0
+	quote := ""
+	started := false
+	for l := i; l < len(lines); l++ {
+		line := strings.TrimRight(lines[l], "\r\n")
+		c := 0
+		if l == i {
+			c = col
+		}
+		for c < len(line) {
+			if quote != "" {
+				switch {
+				case quote[0] == '"' && line[c] == '\\':
+					c += 2
+				case strings.HasPrefix(line[c:], quote):
+					c += len(quote)
+					quote = ""
+					if depth == 0 {
+						return l, c
+					}
+				default:
+					c++
+				}
+				continue
+			}
+			switch ch := line[c]; {
+			case strings.HasPrefix(line[c:], `"""`) || strings.HasPrefix(line[c:], `'''`):
+				quote = line[c : c+3]
+				c += 3
+			case ch == '"' || ch == '\'':
+				quote = string(ch)
+				c++
+			case ch == '[' || ch == '{':
+				depth++
+				c++
+			case ch == ']' || ch == '}':
+				depth--
+				c++
+				if depth == 0 {
+					return l, c
+				}
+			case ch == '#':
+				c = len(line)
+			case ch == ' ' || ch == '\t':
+				if depth == 0 && started {
+					return l, c
+				}
+				c++
+			default:
+				started = true
+				c++
+			}
+		}
+		if depth == 0 && quote == "" && started {
+			return l, len(line)
+		}
+	}
+	return len(lines) - 1, len(strings.TrimRight(lines[len(lines)-1], "\r\n"))
+}
+
+// replaceTOMLTables replaces the tables of a top-level key with the tables of the value, written
+// where the first of them was. The comments above each table stay in place.
+func replaceTOMLTables(lines []string, tables []tomlSection, key string, value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(map[string]interface{}{key: integerValues(copyJSONValue(value))}); err != nil {
+		return nil, err
+	}
+	encoded := buf.String()
+	if !strings.HasPrefix(encoded, "[") {
+		return nil, fmt.Errorf("%s is a table of the file and cannot be set to %s", key, strings.TrimSpace(encoded))
+	}
+
+	var out []string
+	next := 0
+	for i, table := range tables {
+		// The comments after the last key/value of a table lead into the next one, so they stay.
+		end := table.end
+		for end > table.start+1 && (isTOMLComment(lines[end-1]) || strings.TrimSpace(lines[end-1]) == "") {
+			end--
+		}
+		for end < table.end && strings.TrimSpace(lines[end]) == "" {
+			end++
+		}
+		out = append(out, lines[next:table.start]...)
+		if i == 0 {
+			out = append(out, encoded)
+			if tables[len(tables)-1].end < len(lines) && !strings.HasSuffix(encoded, "\n\n") {
+				out = append(out, "\n")
+			}
+		}
+		next = end
+	}
+	out = append(out, lines[next:]...)
+	return []byte(strings.Join(out, "")), nil
+}
+
+// isTOMLComment reports whether a TOML line is a comment
+func isTOMLComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// addTOMLKey adds a key/value to the table of the key path, after its last key/value, or appends
+// the table to the file if it has none
+func addTOMLKey(lines []string, sections []tomlSection, entries []tomlEntry, path []string, value interface{}) ([]byte, error) {
+	inline, err := tomlValue(value)
+	if err != nil {
+		return nil, err
+	}
+	table, leaf := path[:len(path)-1], path[len(path)-1]
+	line := tomlKey(leaf) + " = " + inline + "\n"
+	for s, section := range sections {
+		if section.array || !equalKeys(section.path, table) {
+			continue
+		}
+		at := section.start + 1
+		for _, entry := range entries {
+			if entry.section == s {
+				at = entry.endLine + 1
+			}
+		}
+		if s == 0 && at == 0 {
+			// A file without top-level keys gets them above its first table.
+			if len(sections) == 1 {
+				at = len(lines)
+			} else {
+				line += "\n"
+			}
+		}
+		return joinLines(lines[:at], []string{line}, lines[at:]), nil
+	}
+
+	// A table defined by dotted keys, such as cyclomatic.high = 10, gets another dotted key.
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		section := sections[entry.section]
+		key := append(append([]string{}, section.path...), entry.key...)
+		if section.array || len(key) <= len(table) || !equalKeys(key[:len(table)], table) {
+			continue
+		}
+		line := joinTOMLKey(path[len(section.path):]) + " = " + inline + "\n"
+		return joinLines(lines[:entry.endLine+1], []string{line}, lines[entry.endLine+1:]), nil
+	}
+
+	var text string
+	if len(lines) > 0 {
+		if !strings.HasSuffix(lines[len(lines)-1], "\n") {
+			text = "\n"
+		}
+		text += "\n"
+	}
+	text += "[" + joinTOMLKey(table) + "]\n" + line
+	return joinLines(lines, []string{text}), nil
+}
+
+// tomlValue writes a generic JSON value as an inline TOML value
+func tomlValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("TOML has no null value")
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case string:
+		// JSON string escapes are a subset of the escapes of TOML basic strings.
+		data, err := json.Marshal(v)
+		return string(data), err
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			text, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = text
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			text, err := tomlValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			items[i] = tomlKey(key) + " = " + text
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
+
+// tomlBareKey matches the TOML keys that need no quotes
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey quotes a TOML key unless it is a bare key
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	data, _ := json.Marshal(key)
+	return string(data)
+}
+
+// joinTOMLKey writes a key path as a dotted TOML key
+func joinTOMLKey(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// splitTOMLKey splits a dotted TOML key into its parts, unquoting the quoted ones
+func splitTOMLKey(key string) []string {
+	var parts []string
+	for key = strings.TrimSpace(key); key != ""; {
+		var part string
+		switch key[0] {
+		case '"':
+			end := 1
+			for end < len(key) && key[end] != '"' {
+				if key[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(key) {
+				end = len(key) - 1
+			}
+			part, _ = strconv.Unquote(key[:end+1])
+			key = key[end+1:]
+		case '\'':
+			end := strings.IndexByte(key[1:], '\'') + 1
+			if end == 0 {
+				end = len(key) - 1
+			}
+			part = key[1:end]
+			key = key[end+1:]
+		default:
+			end := strings.IndexAny(key, ". \t")
+			if end < 0 {
+				end = len(key)
+			}
+			part = key[:end]
+			key = key[end:]
+		}
+		parts = append(parts, part)
+		key = strings.TrimPrefix(strings.TrimSpace(key), ".")
+		key = strings.TrimSpace(key)
+	}
+	return parts
+}
+
+// equalKeys reports whether two key paths are the same
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// joinLines joins groups of lines into the content of a file
+func joinLines(groups ...[]string) []byte {
+	var buf bytes.Buffer
+	for _, group := range groups {
+		for _, line := range group {
+			buf.WriteString(line)
+		}
+	}
+	return buf.Bytes()
+}
+
+// copyJSONValue returns a deep copy of a generic JSON value, so that converting it does not
+// change the original
+func copyJSONValue(value interface{}) interface{} {
+	data, _ := json.Marshal(value)
+	var copied interface{}
+	json.Unmarshal(data, &copied)
+	return copied
+}
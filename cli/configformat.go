@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration file formats, detected by the file extension
+const (
+	configJSON = "json"
+	configYAML = "yaml"
+	configTOML = "toml"
+)
+
+// configFormat returns the format of the configuration file at path. Files with an unknown
+// extension are read as JSON.
+func configFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return configYAML
+	case ".toml":
+		return configTOML
+	}
+	return configJSON
+}
+
+// configToJSON converts the content of a configuration file to JSON, so that every format is
+// decoded by the JSON names of the Config fields
+func configToJSON(data []byte, format string) ([]byte, error) {
+	var values map[string]interface{}
+	switch format {
+	case configYAML:
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	case configTOML:
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	default:
+		return data, nil
+	}
+	if values == nil {
+		// An empty YAML file holds no values.
+		values = map[string]interface{}{}
+	}
+	return json.Marshal(values)
+}
+
+// configFromJSON converts a configuration encoded as JSON to the format
+func configFromJSON(data []byte, format string) ([]byte, error) {
+	switch format {
+	case configJSON:
+		return data, nil
+	case configYAML:
+		// JSON is YAML, so decoding it into a node keeps the order of the keys. Only the style of
+		// the nodes has to change from JSON's flow style to block style.
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		blockStyle(&node)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(integerValues(values)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// integerValues turns the whole numbers decoded from JSON, which are all float64, into integers,
+// so that TOML writes 10 rather than 10.0
+func integerValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = integerValues(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = integerValues(child)
+		}
+	}
+	return value
+}
+
+// blockStyle clears the style of a YAML node and its children, so that they are written in block style
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
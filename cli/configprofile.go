@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultProfile is the profile applied when no profile is selected, if the configuration has one
+const defaultProfile = "default"
+
+// configProfile is the profile selected with --profile or ZEDS_PROFILE
+var configProfile string
+
+// profileNames returns the names of the profiles of the configuration, sorted
+func (c *Config) profileNames() []string {
+	var names []string
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile applies the values of the named profile over the configuration and returns the
+// name of the profile applied. Without a name, the default profile is applied if the
+// configuration has one.
+func (c *Config) applyProfile(name string) (string, error) {
+	explicit := name != ""
+	if !explicit {
+		name = defaultProfile
+	}
+	values, ok := c.Profiles[name]
+	if !ok {
+		if explicit {
+			return "", fmt.Errorf("unknown profile %q. Valid profiles: %s", name, strings.Join(c.profileNames(), ", "))
+		}
+		return "", nil
+	}
+	profiles := c.Profiles
+	if err := json.Unmarshal(values, c); err != nil {
+		return "", fmt.Errorf("profiles: %s: %v", name, err)
+	}
+	// A profile selects values, not other profiles.
+	c.Profiles = profiles
+	if err := c.Validate(); err != nil {
+		return "", fmt.Errorf("profiles: %s: %v", name, err)
+	}
+	return name, nil
+}
+
+// validateProfiles checks that every profile yields a valid configuration
+func (c *Config) validateProfiles() error {
+	for _, name := range c.profileNames() {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(c.Profiles[name], &values); err != nil {
+			return fmt.Errorf("profiles: %s must be an object", name)
+		}
+		if _, ok := values["profiles"]; ok {
+			return fmt.Errorf("profiles: %s cannot have profiles", name)
+		}
+		cfg, err := c.clone()
+		if err != nil {
+			return err
+		}
+		cfg.Profiles = nil
+		if err := json.Unmarshal(c.Profiles[name], cfg); err != nil {
+			return fmt.Errorf("profiles: %s: %v", name, err)
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("profiles: %s: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+	"golang.org/x/tools/go/packages"
+)
+
+// packagesReport is the JSON output of `zeds packages`
+type packagesReport struct {
+	Packages []analyzer.PackageMetrics `json:"packages"`
+	Cycles   [][]string                `json:"cycles"`
+	Unused   []analyzer.UnusedExport   `json:"unused"`
+}
+
+// handlePackagesCommand prints the package metrics, import cycles and unused exports of the packages
+// matching the patterns, ./... by default. With --baseline, the API surface of each package is
+// compared with a previous JSON report.
+func handlePackagesCommand(args []string) {
+	format := "text"
+	baselinePath, outputPath := "", ""
+	flags := newFlagSet("packages", "[options] [package pattern...]")
+	flags.stringVar(&format, "format", "", "write the report in the `format`: text, json")
+	flags.stringVar(&baselinePath, "baseline", "", "compare the API surface with the JSON `report`")
+	flags.stringVar(&outputPath, "output", "o", "write the report to the `file` instead of standard output, creating its directory")
+	patterns := parseCommandArgs(flags, args[1:])
+	if format != "text" && format != "json" {
+		failUsage(flags, fmt.Errorf("unknown format '%s'", format))
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	var baseline map[string]int
+	if baselinePath != "" {
+		if baseline, err = readAPIBaseline(baselinePath); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error reading baseline: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	}
+	if outputPath != "" {
+		if err := redirectOutput(outputPath); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error creating output file: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+	}
+	report, err := loadPackageMetrics(patterns)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading packages: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if format == "json" {
+		if err := writeJSON(report); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error writing report: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+		return
+	}
+	printHeader()
+	printPackageMetrics(report.Packages, cfg)
+	printAPISurface(report.Packages, baseline)
+	printPackageImports(report.Packages, cfg)
+	printImportCycles(report.Cycles)
+	printUnusedExports(report.Unused)
+	printFooter()
+}
+
+// loadPackageMetrics computes the package metrics of the packages matching the patterns, sorted by
+// path, and finds the import cycles and unused exports among them. Afferent coupling, dependency
+// depth, cycles and references only take the matched packages into account, so they are relative
+// to the patterns, e.g. module-wide for ./... Test files are not loaded, so an export only used by
+// tests is unused. Import cycle and type errors are expected here, while other errors loading a
+// package are fatal.
+func loadPackageMetrics(patterns []string) (packagesReport, error) {
+	config := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedSyntax |
+		packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule}
+	pkgs, err := packages.Load(config, patterns...)
+	if err != nil {
+		return packagesReport{}, err
+	}
+	graph := make(analyzer.ImportGraph)
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			if pkgErr.Kind != packages.TypeError && !strings.Contains(pkgErr.Msg, "import cycle not allowed") {
+				return packagesReport{}, fmt.Errorf("%s: %v", pkg.PkgPath, pkgErr)
+			}
+		}
+		graph[pkg.PkgPath] = nil
+	}
+	afferent := make(map[string]int)
+	efferent := make(map[string]int)
+	for _, pkg := range pkgs {
+		for _, path := range fileImports(pkg) {
+			if _, ok := graph[path]; ok {
+				afferent[path]++
+				graph[pkg.PkgPath] = append(graph[pkg.PkgPath], path)
+			}
+			if _, ok := graph[path]; ok || !analyzer.IsStandardImport(path) {
+				efferent[pkg.PkgPath]++
+			}
+		}
+	}
+	referenced := make(map[types.Object]bool)
+	for _, pkg := range pkgs {
+		analyzer.CollectReferences(pkg.Syntax, pkg.TypesInfo, referenced)
+	}
+	depths := graph.DependencyDepths()
+	report := packagesReport{Cycles: graph.ImportCycles(), Unused: []analyzer.UnusedExport{}}
+	for _, pkg := range pkgs {
+		exported, abstract := analyzer.CountExportedTypes(pkg.Syntax)
+		metrics := analyzer.NewPackageMetrics(pkg.PkgPath, afferent[pkg.PkgPath], efferent[pkg.PkgPath], exported, abstract)
+		metrics.Depth = depths[pkg.PkgPath]
+		metrics.Documentation = analyzer.CalculateDocCoverage(pkg.Syntax)
+		metrics.API = analyzer.CalculateAPISurface(pkg.Syntax)
+		if pkg.Module != nil {
+			metrics.Imports = analyzer.ClassifyImports(fileImports(pkg), pkg.Module.Path)
+		} else {
+			metrics.Imports = analyzer.ClassifyImports(fileImports(pkg), "")
+		}
+		report.Packages = append(report.Packages, metrics)
+		report.Unused = append(report.Unused, analyzer.FindUnusedExports(pkg.Fset, pkg.PkgPath, pkg.Syntax, pkg.TypesInfo, referenced)...)
+	}
+	sort.Slice(report.Packages, func(i, j int) bool { return report.Packages[i].Path < report.Packages[j].Path })
+	if report.Cycles == nil {
+		report.Cycles = [][]string{}
+	}
+	return report, nil
+}
+
+// fileImports returns the import paths of the files of a package. They are read from the syntax
+// because the package's resolved imports leave out the import that closes a cycle.
+func fileImports(pkg *packages.Package) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err == nil && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// printPackageMetrics prints the package metrics as a table
+func printPackageMetrics(metrics []analyzer.PackageMetrics, cfg *Config) {
+	fmt.Fprintln(stdout, ColorCyan+"Package Metrics:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+	fmt.Fprintf(stdout, "%-40s %4s %4s %6s %6s %6s %5s %6s\n", "Package", "Ca", "Ce", "I", "A", "D", "Depth", "Doc%")
+	for _, m := range metrics {
+		fmt.Fprintf(stdout, "%-40s %4d %4d %6.2f %6.2f %s%6.2f%s %5d %s%6.1f%s\n", truncate(m.Path, 40), m.Afferent, m.Efferent,
+			m.Instability, m.Abstractness, GetColorForDistance(m.Distance, cfg), m.Distance, ColorReset, m.Depth,
+			GetColorForDocCoverage(m.Documentation.Percent, cfg), m.Documentation.Percent, ColorReset)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+	fmt.Fprintln(stdout, "Ca: afferent coupling, Ce: efferent coupling, I: instability, A: abstractness, D: distance from the main sequence,")
+	fmt.Fprintln(stdout, "Depth: longest import chain within the analyzed packages, Doc%: share of exported identifiers with doc comments")
+}
+
+// readAPIBaseline reads the total API surface of each package from a previous JSON report of `zeds packages`
+func readAPIBaseline(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report packagesReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	totals := make(map[string]int, len(report.Packages))
+	for _, m := range report.Packages {
+		totals[m.Path] = m.API.Total
+	}
+	return totals, nil
+}
+
+// printAPISurface prints the exported identifiers of each package, and how their number changed
+// since the baseline when there is one. Growth is shown in yellow, as it is not a problem in
+// itself but should be deliberate.
+func printAPISurface(metrics []analyzer.PackageMetrics, baseline map[string]int) {
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"API Surface:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+	if baseline == nil {
+		fmt.Fprintf(stdout, "%-40s %5s %7s %5s %4s %5s\n", "Package", "Funcs", "Methods", "Types", "Vars", "Total")
+	} else {
+		fmt.Fprintf(stdout, "%-40s %5s %7s %5s %4s %5s %6s\n", "Package", "Funcs", "Methods", "Types", "Vars", "Total", "Change")
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(stdout, "%-40s %5d %7d %5d %4d %5d", truncate(m.Path, 40), m.API.Functions, m.API.Methods, m.API.Types, m.API.Variables, m.API.Total)
+		if baseline != nil {
+			fmt.Fprint(stdout, " "+formatAPIChange(m.API.Total, baseline, m.Path))
+		}
+		fmt.Fprintln(stdout)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+}
+
+// formatAPIChange formats the change of a package's API surface since the baseline, e.g. "+3"
+func formatAPIChange(total int, baseline map[string]int, path string) string {
+	before, ok := baseline[path]
+	switch {
+	case !ok:
+		return ColorYellow + fmt.Sprintf("%6s", "new") + ColorReset
+	case total > before:
+		return ColorYellow + fmt.Sprintf("%+6d", total-before) + ColorReset
+	case total < before:
+		return ColorGreen + fmt.Sprintf("%+6d", total-before) + ColorReset
+	}
+	return fmt.Sprintf("%6d", 0)
+}
+
+// printPackageImports prints the imports of each package by origin
+func printPackageImports(metrics []analyzer.PackageMetrics, cfg *Config) {
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"Imports:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+	fmt.Fprintf(stdout, "%-40s %6s %6s %11s\n", "Package", "Stdlib", "Module", "Third-party")
+	for _, m := range metrics {
+		fmt.Fprintf(stdout, "%-40s %6d %6d %s%11d%s\n", truncate(m.Path, 40), m.Imports.Stdlib, m.Imports.Module,
+			GetColorForThirdPartyImports(m.Imports.ThirdParty, cfg), m.Imports.ThirdParty, ColorReset)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------------------------"+ColorReset)
+}
+
+// printImportCycles lists the groups of packages that import each other
+func printImportCycles(cycles [][]string) {
+	fmt.Fprintln(stdout)
+	if len(cycles) == 0 {
+		fmt.Fprintln(stdout, ColorGreen+"No import cycles found."+ColorReset)
+		return
+	}
+	fmt.Fprintln(stdout, ColorRed+fmt.Sprintf("Import cycles (%d):", len(cycles))+ColorReset)
+	for _, cycle := range cycles {
+		fmt.Fprintln(stdout, "  - "+ColorRed+strings.Join(cycle, " ↔ ")+ColorReset)
+	}
+}
+
+// printUnusedExports lists the exported functions and types that nothing in the analyzed packages
+// refers to, with the complexity that removing them would remove
+func printUnusedExports(unused []analyzer.UnusedExport) {
+	fmt.Fprintln(stdout)
+	if len(unused) == 0 {
+		fmt.Fprintln(stdout, ColorGreen+"No unused exports found."+ColorReset)
+		return
+	}
+	complexity := 0
+	for _, u := range unused {
+		complexity += u.Complexity
+	}
+	fmt.Fprintln(stdout, ColorYellow+fmt.Sprintf("Unused exports (%d, removable complexity %d):", len(unused), complexity)+ColorReset)
+	for _, u := range unused {
+		fmt.Fprintf(stdout, "  - %s %s%s.%s%s (%s:%d): CC %d\n", u.Kind, ColorCyan, u.Package, u.Name, ColorReset, reportURI(u.File), u.Line, u.Complexity)
+	}
+}
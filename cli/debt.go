@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// fileDebt holds the debt notes found in a file and the results of its functions
+type fileDebt struct {
+	file    string
+	notes   []analyzer.DebtMarker
+	results []analyzer.MethodResult
+}
+
+// TechnicalDebt is the estimated time to fix the threshold violations of some code, in the manner of
+// the SQALE method.
+type TechnicalDebt struct {
+	Minutes float64 `json:"minutes"`
+	// Ratio is the remediation time over the estimated time to develop the code.
+	Ratio float64 `json:"ratio"`
+	// Rating grades the ratio from A, up to 5%, to E, over 50%.
+	Rating string `json:"rating"`
+}
+
+// sqaleRatings lists the highest debt ratio of each rating; higher ratios are rated E
+var sqaleRatings = []struct {
+	label    string
+	maxRatio float64
+}{{"A", 0.05}, {"B", 0.1}, {"C", 0.2}, {"D", 0.5}}
+
+// EstimateTechnicalDebt estimates the remediation time of the threshold violations of functions.
+// Each violation costs the configured minutes for every unit its metric is beyond the medium
+// threshold. The development time is the lines of code of the functions times the configured
+// minutes per line.
+func EstimateTechnicalDebt(results []analyzer.MethodResult, cfg *Config) TechnicalDebt {
+	var debt TechnicalDebt
+	lines := 0
+	for _, res := range results {
+		lines += res.LOC
+	}
+	for _, v := range findViolations("", results, cfg) {
+		medium, _ := v.metric.limits(cfg)
+		debt.Minutes += math.Abs(v.metric.value(v.function)-medium) * cfg.TechnicalDebt.MinutesPerUnit[v.metric.key]
+	}
+	if lines > 0 {
+		debt.Ratio = debt.Minutes / (float64(lines) * cfg.TechnicalDebt.MinutesPerLine)
+	}
+	debt.Rating = "E"
+	for _, rating := range sqaleRatings {
+		if debt.Ratio <= rating.maxRatio {
+			debt.Rating = rating.label
+			break
+		}
+	}
+	return debt
+}
+
+// formatDebt describes technical debt, e.g. "2h 5m (debt ratio 3.1%, rating A)"
+func formatDebt(debt TechnicalDebt) string {
+	return fmt.Sprintf("%s (debt ratio %.1f%%, rating %s%s%s)", formatMinutes(debt.Minutes), debt.Ratio*100,
+		GetColorForRating(debt.Rating), debt.Rating, ColorReset)
+}
+
+// formatMinutes formats a duration in minutes as hours and minutes, e.g. "2h 5m"
+func formatMinutes(minutes float64) string {
+	total := int(math.Round(minutes))
+	if total < 60 {
+		return fmt.Sprintf("%dm", total)
+	}
+	return fmt.Sprintf("%dh %dm", total/60, total%60)
+}
+
+// GetColorForRating returns green for the A and B debt ratings, yellow for C and red for D and E
+func GetColorForRating(rating string) string {
+	switch rating {
+	case "A", "B":
+		return ColorGreen
+	case "C":
+		return ColorYellow
+	}
+	return ColorRed
+}
+
+// printDebtMarkers lists the debt notes of a file with their lines
+func printDebtMarkers(notes []analyzer.DebtMarker) {
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"Debt Markers:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	for _, note := range notes {
+		fmt.Fprintf(stdout, "  - line %d: %s%s%s\n", note.Line, ColorYellow, note.Text, ColorReset)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+}
+
+// countMarkers formats the number of notes per marker, in the order of the configured markers
+func countMarkers(notes []analyzer.DebtMarker, markers []string) string {
+	counts := make(map[string]int)
+	for _, note := range notes {
+		counts[note.Marker]++
+	}
+	var parts []string
+	for _, marker := range markers {
+		if counts[marker] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", marker, counts[marker]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printDebtSummary prints the estimated remediation time and the debt notes of a run, in total and
+// by package directory, relative to root
+func printDebtSummary(root string, files []fileDebt, cfg *Config) {
+	var allNotes []analyzer.DebtMarker
+	var allResults []analyzer.MethodResult
+	notesByDir := make(map[string][]analyzer.DebtMarker)
+	resultsByDir := make(map[string][]analyzer.MethodResult)
+	for _, f := range files {
+		allNotes = append(allNotes, f.notes...)
+		allResults = append(allResults, f.results...)
+		dir := filepath.Dir(f.file)
+		if rel, err := filepath.Rel(root, dir); err == nil {
+			dir = rel
+		}
+		notesByDir[dir] = append(notesByDir[dir], f.notes...)
+		resultsByDir[dir] = append(resultsByDir[dir], f.results...)
+	}
+	if len(allResults) == 0 && len(allNotes) == 0 {
+		return
+	}
+	dirs := make([]string, 0, len(resultsByDir))
+	for dir := range resultsByDir {
+		if len(resultsByDir[dir]) > 0 || len(notesByDir[dir]) > 0 {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	fmt.Fprintln(stdout, ColorCyan+"Technical Debt:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	fmt.Fprintln(stdout, "Remediation time: "+formatDebt(EstimateTechnicalDebt(allResults, cfg)))
+	if len(allNotes) > 0 {
+		fmt.Fprintf(stdout, "Debt markers: %d (%s)\n", len(allNotes), countMarkers(allNotes, cfg.DebtMarkers))
+	}
+	for _, dir := range dirs {
+		line := fmt.Sprintf("  - %s%s%s: %s", ColorCyan, filepath.ToSlash(dir), ColorReset, formatDebt(EstimateTechnicalDebt(resultsByDir[dir], cfg)))
+		if notes := notesByDir[dir]; len(notes) > 0 {
+			line += fmt.Sprintf(", debt markers %d (%s)", len(notes), countMarkers(notes, cfg.DebtMarkers))
+		}
+		fmt.Fprintln(stdout, line)
+	}
+	fmt.Fprintln(stdout)
+}
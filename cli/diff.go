@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// lineRange is an inclusive range of line numbers
+type lineRange struct {
+	start, end int
+}
+
+// hunkHeader matches the new-file side of a unified diff hunk header, e.g. "@@ -10,2 +12,3 @@"
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// changedGoFiles asks git for the Go files added or modified since the base ref, including
+// uncommitted changes, and returns their changed line ranges keyed by path relative to the working directory
+func changedGoFiles(baseRef string) (map[string][]lineRange, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required for --diff: %v", err)
+	}
+	root, err := gitRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", "--diff-filter=AMR",
+		"--src-prefix=a/", "--dst-prefix=b/", baseRef, "--", "*.go")
+	cmd.Dir = root
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s: %v", baseRef, err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return parseDiff(out, root, wd), nil
+}
+
+// gitRoot returns the top-level directory of the git repository holding the working directory
+func gitRoot() (string, error) {
+	top, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository: %v", err)
+	}
+	return filepath.FromSlash(strings.TrimSpace(string(top))), nil
+}
+
+// parseDiff collects the new-file line ranges of each file in a unified diff produced with --unified=0.
+// A pure deletion is recorded as the line before it, so the function it was removed from counts as changed.
+func parseDiff(diff []byte, root, wd string) map[string][]lineRange {
+	changed := map[string][]lineRange{}
+	var file string
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "+++ b/"); ok {
+			file = filepath.Join(root, filepath.FromSlash(name))
+			if rel, err := filepath.Rel(wd, file); err == nil {
+				file = rel
+			}
+			changed[file] = nil
+			continue
+		}
+		m := hunkHeader.FindStringSubmatch(line)
+		if m == nil || file == "" {
+			continue
+		}
+		start, _ := strconv.Atoi(m[1])
+		count := 1
+		if m[2] != "" {
+			count, _ = strconv.Atoi(m[2])
+		}
+		end := start + count - 1
+		if count == 0 {
+			end = start
+		}
+		changed[file] = append(changed[file], lineRange{start: start, end: end})
+	}
+	return changed
+}
+
+// filterChanged keeps only the functions that overlap a changed line range
+func filterChanged(results []analyzer.MethodResult, ranges []lineRange) []analyzer.MethodResult {
+	var changed []analyzer.MethodResult
+	for _, res := range results {
+		for _, r := range ranges {
+			if r.start <= res.EndLine && r.end >= res.Line {
+				changed = append(changed, res)
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// analyzeDiff analyzes the Go files changed since the base ref, reporting only the functions
+// that were added or modified
+func analyzeDiff(baseRef string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	changed, err := changedGoFiles(baseRef)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	var files []string
+	for file := range changed {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	if len(files) == 0 {
+		fmt.Fprintln(stdout, ColorGreen+"No Go files changed since "+baseRef+"."+ColorReset)
+		return
+	}
+	opts.changedLines = changed
+	analyzeGroups([]fileGroup{{files: files}}, ".", cfg, opts, budgets)
+}
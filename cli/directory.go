@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// leastDocumentedFiles is the number of files listed in the directory documentation summary
+const leastDocumentedFiles = 5
+
+// collectGoFiles returns the Go source files in dir, sorted by path.
+// Subdirectories are only searched when recursive is set, and skipped when the go tool ignores them.
+func collectGoFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && (!recursive || ignoredDir(entry.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(entry.Name(), ".go") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// recursiveDir resolves the "/..." suffix of a directory the way the go tool does: "./..." names
+// the directory "." together with all of its subdirectories
+func recursiveDir(dir string, recursive bool) (string, bool) {
+	if dir == "..." || strings.HasSuffix(dir, "/...") {
+		return filepath.Clean(strings.TrimSuffix(dir, "...")), true
+	}
+	return dir, recursive
+}
+
+// ignoredDir reports whether the go tool ignores a directory of that name when matching ./...:
+// testdata, vendor and directories whose name starts with a dot or an underscore. Their files are
+// often fixtures that are not meant to build, or even to parse.
+func ignoredDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// fileGroup is a set of files reported together, such as a directory tree or a Go package
+type fileGroup struct {
+	// kind names what the group is, e.g. "Package" or "Module", in its heading
+	kind  string
+	name  string
+	files []string
+	// fsys holds the files when they are not read from disk, e.g. for an archive
+	fsys fs.FS
+	// calls holds the call graph metrics of each function by file and line, when type information is available
+	calls map[string]map[int]callCounts
+}
+
+// readFile reads a file of the group from its file system, or from disk when it has none
+func (g fileGroup) readFile(path string) ([]byte, error) {
+	if g.fsys != nil {
+		return fs.ReadFile(g.fsys, path)
+	}
+	return os.ReadFile(path)
+}
+
+// analyzeDirectory analyzes every Go file in the directory, printing the results of each file
+// followed by the aggregate results. When a recursive run finds several modules, the results
+// are grouped by module. It exits with a failure status like a single-file run.
+func analyzeDirectory(dir string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	files, err := collectGoFiles(dir, opts.recursive)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error reading directory: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	groups := []fileGroup{{files: files}}
+	if opts.recursive {
+		modules, err := discoverModules(dir)
+		if err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error discovering modules: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+		if len(modules) > 1 {
+			groups = groupByModule(files, modules)
+		}
+	}
+	analyzeGroups(groups, dir, cfg, opts, budgets)
+}
+
+// analyzeGroups analyzes the files of each group in turn. In the text format, named groups are
+// introduced by a heading and followed by their own aggregate results, and the run ends with the
+// overall aggregate and documentation summary. File names in the summary are shown relative to root.
+// In the ndjson format, the functions of each file are written as soon as the file is analyzed;
+// the other machine-readable formats are written once all files have been analyzed.
+func analyzeGroups(groups []fileGroup, root string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	excludes, err := readExcludes(root, opts.excludes)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+
+	text := opts.format == "text" && !opts.quiet
+	if text {
+		printHeader()
+	}
+	var all []analyzer.MethodResult
+	var densities []analyzer.FileDensity
+	var violations []BudgetViolation
+	var collected []fileResults
+	// analyzed keeps the results of every file for the type and risk summaries
+	var analyzed []fileResults
+	// gated keeps the results of every file in any format for the quality gate
+	var gated []fileResults
+	var debt []fileDebt
+	skipped, excludedFiles, unparsable := 0, 0, 0
+	failed := false
+	total := 0
+	for _, group := range groups {
+		total += len(group.files)
+	}
+	bar := newProgress(total, opts)
+	exitHooks = append(exitHooks, bar.clear)
+	for _, group := range groups {
+		if text && group.kind != "" {
+			heading := group.kind
+			if group.name != "" {
+				heading += ": " + group.name
+			}
+			fmt.Fprintln(stdout, Bold+ColorMagenta+heading+ColorReset)
+			fmt.Fprintln(stdout)
+		}
+		var groupResults []analyzer.MethodResult
+		groupFiles := 0
+		for _, path := range group.files {
+			bar.advance()
+			if excludedFile(root, path, excludes) {
+				excludedFiles++
+				logVerbose(opts, "Skipped %s: matches an exclude pattern.", path)
+				continue
+			}
+			src, err := group.readFile(path)
+			if err != nil {
+				fmt.Fprintln(stderr, ColorRed+"Error reading "+path+": "+err.Error()+ColorReset)
+				exit(exitError)
+			}
+			// A file that does not parse is counted and skipped, so that one broken file does not stop the run.
+			buildConstraint, err := analyzer.BuildConstraint(src)
+			if err != nil {
+				unparsable++
+				logVerbose(opts, "Skipped %s, which does not parse: %v", path, err)
+				continue
+			}
+			// Package patterns are resolved with the build tags already applied.
+			if opts.packages == nil && opts.buildTags != nil && !analyzer.MatchesTags(buildConstraint, opts.buildTags) {
+				skipped++
+				logVerbose(opts, "Skipped %s: build constraint '%s' does not match tags %s.", path, buildConstraint, strings.Join(opts.buildTags, ","))
+				continue
+			}
+
+			// Path overrides of the configuration apply to everything reported for the file.
+			fileCfg := cfg.ForFile(path)
+			results, commentDensity, excluded, err := analyzeSource(path, src, fileCfg, opts)
+			if err != nil {
+				unparsable++
+				logVerbose(opts, "Skipped %s, which does not parse: %v", path, err)
+				continue
+			}
+			if calls, ok := group.calls[path]; ok {
+				for i := range results {
+					results[i].FanIn = calls[results[i].Line].fanIn
+					results[i].FanOut = calls[results[i].Line].fanOut
+				}
+			}
+			groupResults = append(groupResults, results...)
+			gated = append(gated, fileResults{file: path, results: results})
+			groupFiles++
+			densities = append(densities, analyzer.FileDensity{
+				Path:           path,
+				Lines:          strings.Count(string(src), "\n") + 1,
+				CommentDensity: commentDensity,
+			})
+
+			if opts.budgetsPath != "" {
+				violations = append(violations, CheckBudgets(path, results, budgets, fileCfg)...)
+			}
+			failed = failed || failOnViolated(results, fileCfg, opts)
+			if opts.format == "ndjson" {
+				if err := writeNDJSON(path, results); err != nil {
+					fmt.Fprintln(stderr, "Error writing results: "+err.Error())
+					exit(exitError)
+				}
+				continue
+			}
+			if !text {
+				collected = append(collected, fileResults{file: path, results: results})
+				continue
+			}
+
+			analyzed = append(analyzed, fileResults{file: path, results: results})
+			fmt.Fprintln(stdout, fileHeading(path, results, fileCfg))
+			if buildConstraint != nil {
+				fmt.Fprintln(stdout, Italic+ColorYellow+"Build constraint: "+buildConstraint.String()+ItalicReset+ColorReset)
+			}
+			printImportProfile(fileImportProfile(path, src), fileCfg)
+			printFileResults(results, commentDensity, excluded, fileCfg, opts)
+			printTypeResults(analyzeTypes(path, src, fileCfg, opts), fileCfg)
+			notes := analyzer.FindDebtMarkers(src, fileCfg.DebtMarkers)
+			printDebtMarkers(notes)
+			debt = append(debt, fileDebt{file: path, notes: notes, results: results})
+			if opts.lint {
+				printLintFindings(append(LintResults(results, fileCfg), LintFile(src, fileCfg)...))
+			}
+			fmt.Fprintln(stdout)
+		}
+		if text && group.name != "" && len(groups) > 1 && groupFiles > 0 {
+			printAggregateResults(group.kind+" Results ("+group.name+"):", groupResults, groupFiles, cfg)
+		}
+		all = append(all, groupResults...)
+	}
+	bar.clear()
+
+	if containsString(collectedFormats, opts.format) {
+		if err := writeFileReports(collected, cfg, opts); err != nil {
+			fmt.Fprintln(stderr, "Error writing report: "+err.Error())
+			exit(exitError)
+		}
+	}
+	logElapsed(opts, len(gated))
+	gate := EvaluateQualityGate(gated, cfg)
+	if opts.quiet && opts.format == "text" {
+		printQuietReport(collected, violations, failed, gate, cfg, opts)
+	} else if !text {
+		printQualityGate(stderr, gate)
+	}
+	if opts.format == "ndjson" {
+		summary := NewRunSummary(gated, cfg, opts)
+		summary.UnparsableFiles = unparsable
+		if err := writeSummaryRecord(summary); err != nil {
+			fmt.Fprintln(stderr, "Error writing results: "+err.Error())
+			exit(exitError)
+		}
+	}
+	if !text {
+		if len(violations) > 0 || failed || !gatePassed(gate) {
+			exit(exitViolations)
+		}
+		return
+	}
+	if len(densities) == 0 {
+		fmt.Fprintln(stdout, ColorRed+"No Go files found."+ColorReset)
+		return
+	}
+	if excludedFiles > 0 {
+		fmt.Fprintln(stdout, Italic+ColorYellow+fmt.Sprintf("Excluded %d file(s) matching exclude patterns.", excludedFiles)+ItalicReset+ColorReset)
+	}
+	if skipped > 0 {
+		fmt.Fprintln(stdout, Italic+ColorYellow+fmt.Sprintf("Skipped %d file(s) whose build constraint does not match tags %s.",
+			skipped, strings.Join(opts.buildTags, ","))+ItalicReset+ColorReset)
+	}
+	printAggregateResults("Aggregate Results:", all, len(densities), cfg)
+	if ranksResults(opts) {
+		printWorstFunctions(analyzed, opts)
+	}
+	if reportsAllFunctions(opts) {
+		printWeightedMethods(analyzed, cfg)
+	}
+	printDebtSummary(root, debt, cfg)
+	if opts.packages != nil {
+		printRiskiestFunctions(analyzed, cfg)
+	}
+	printDocumentationSummary(root, densities)
+	if opts.budgetsPath != "" {
+		printBudgetViolations(violations)
+	}
+	if failed {
+		fmt.Fprintln(stdout, ColorRed+"Failed: a function breaches a threshold requested with --fail-on ("+strings.Join(opts.failOn, ", ")+")."+ColorReset)
+	}
+	summary := NewRunSummary(gated, cfg, opts)
+	summary.UnparsableFiles = unparsable
+	printRunSummary(summary)
+	printQualityGate(stdout, gate)
+	printFooter()
+
+	if len(violations) > 0 || failed || !gatePassed(gate) {
+		exit(exitViolations)
+	}
+}
+
+// printAggregateResults prints the totals over the analyzed files and their overall score
+func printAggregateResults(title string, results []analyzer.MethodResult, files int, cfg *Config) {
+	summary := analyzer.Summarize(results)
+	fmt.Fprintln(stdout, ColorCyan+title+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	fmt.Fprintf(stdout, "Files analyzed: %d\n", files)
+	fmt.Fprintf(stdout, "Functions: %d\n", summary.Functions)
+	fmt.Fprintf(stdout, "Total Cyclomatic Complexity: %d\n", summary.TotalCyclomatic)
+	fmt.Fprintf(stdout, "Total LOC: %d\n", summary.TotalLOC)
+	if summary.Functions > 0 {
+		fmt.Fprintln(stdout, "Average Maintainability Index:", GetColorForMI(summary.AverageMI, cfg), fmt.Sprintf("%.2f", summary.AverageMI), ColorReset)
+		score := CalculateScore(results, cfg)
+		scoreColor := GetColorForScore(score, cfg)
+		fmt.Fprintln(stdout, Bold+"Quality Score:"+ColorReset, scoreColor, fmt.Sprintf("%.1f", score), ColorReset)
+		fmt.Fprintln(stdout, Bold+"Overall grade:"+ColorReset, scoreColor, GetGradeForScore(score, cfg), ColorReset)
+	}
+	fmt.Fprintln(stdout)
+}
+
+// printDocumentationSummary prints the LOC-weighted package comment density and the least documented files
+func printDocumentationSummary(dir string, densities []analyzer.FileDensity) {
+	fmt.Fprintln(stdout, ColorCyan+"Documentation Summary:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	fmt.Fprintln(stdout, Bold+"Package comment density (%):"+ColorReset, fmt.Sprintf("%.1f", analyzer.WeightedCommentDensity(densities)*100))
+	fmt.Fprintln(stdout)
+
+	sorted := append([]analyzer.FileDensity(nil), densities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CommentDensity != sorted[j].CommentDensity {
+			return sorted[i].CommentDensity < sorted[j].CommentDensity
+		}
+		return sorted[i].Lines > sorted[j].Lines
+	})
+	if len(sorted) > leastDocumentedFiles {
+		sorted = sorted[:leastDocumentedFiles]
+	}
+	fmt.Fprintln(stdout, ColorCyan+"Least documented files:"+ColorReset)
+	for _, f := range sorted {
+		name := f.Path
+		if rel, err := filepath.Rel(dir, f.Path); err == nil {
+			name = rel
+		}
+		fmt.Fprintf(stdout, "  - %s%s%s: %.1f%% comments, %d lines\n", ColorCyan, name, ColorReset, f.CommentDensity*100, f.Lines)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+}
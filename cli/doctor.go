@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// isTerminal reports whether the given file is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// handleDoctorCommand prints diagnostics about the zeds installation and its effective configuration
+func handleDoctorCommand(args []string) {
+	parseCommandOptions(newFlagSet("doctor", ""), args[1:])
+	printHeader()
+	fmt.Fprintln(stdout, ColorCyan+"Environment:"+ColorReset)
+	fmt.Fprintln(stdout, "  - Zeds version:", GetVersion())
+	if commit := GetCommit(); commit != "" {
+		fmt.Fprintln(stdout, "  - Commit:", commit)
+	}
+	fmt.Fprintln(stdout, "  - Go version:", runtime.Version(), runtime.GOOS+"/"+runtime.GOARCH)
+	fmt.Fprintln(stdout, "  - Stdout is a terminal:", isTerminal(os.Stdout))
+	if os.Getenv("NO_COLOR") != "" {
+		fmt.Fprintln(stdout, "  - NO_COLOR is set")
+	}
+	fmt.Fprintln(stdout, "  - Color output:", stdout.color, "(--color="+colorMode+")")
+	fmt.Fprintln(stdout)
+
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		absPath = configPath
+	}
+	fmt.Fprintln(stdout, ColorCyan+"Configuration:"+ColorReset)
+	fmt.Fprintln(stdout, "  - Config path:", absPath)
+
+	// The configuration is loaded exactly as analyze loads it, and the steps it took are shown.
+	cfg, steps, err := loadConfigSteps()
+	if err != nil {
+		switch steps.failed {
+		case configStepFile:
+			fmt.Fprintln(stdout, ColorRed+"  - Config file is invalid: "+err.Error()+ColorReset)
+		case configStepProfile:
+			fmt.Fprintln(stdout, ColorRed+"  - Profile is invalid: "+err.Error()+ColorReset)
+		default:
+			fmt.Fprintln(stdout, ColorRed+"  - Environment overrides are invalid: "+err.Error()+ColorReset)
+		}
+		exit(exitError)
+	}
+	if steps.path == "" {
+		fmt.Fprintln(stdout, "  - Config file not found, using default values")
+	} else {
+		fmt.Fprintln(stdout, ColorGreen+"  - Config file is valid"+ColorReset)
+	}
+	if steps.profile != "" {
+		fmt.Fprintln(stdout, "  - Profile:", steps.profile)
+	}
+	if len(steps.environment) > 0 {
+		fmt.Fprintln(stdout, "  - Environment overrides:", strings.Join(steps.environment, ", "))
+	}
+	fmt.Fprintln(stdout)
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error encoding config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"Effective configuration:"+ColorReset)
+	fmt.Fprintln(stdout, string(data))
+}
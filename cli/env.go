@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// envPrefix starts the names of the environment variables that configure zeds
+const envPrefix = "ZEDS_"
+
+// envName returns the environment variable for a configuration key path or an option name, e.g.
+// ZEDS_CYCLOMATIC_HIGH for cyclomatic.high and ZEDS_FAIL_ON for --fail-on
+func envName(parts ...string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		for j, r := range part {
+			switch {
+			case r == '-' || r == '.':
+				b.WriteByte('_')
+			case unicode.IsUpper(r) && j > 0:
+				b.WriteByte('_')
+				b.WriteRune(r)
+			default:
+				b.WriteRune(unicode.ToUpper(r))
+			}
+		}
+	}
+	return b.String()
+}
+
+// applyConfigEnvironment overrides the configuration values that have a ZEDS_ environment variable
+// named after their key, e.g. ZEDS_CYCLOMATIC_HIGH=15 for cyclomatic.high. Lists are separated by
+// commas. It returns the names of the variables applied.
+func applyConfigEnvironment(cfg *Config) ([]string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	applied, err := applyEnvironmentValues(values, nil)
+	if err != nil || len(applied) == 0 {
+		return nil, err
+	}
+	if data, err = json.Marshal(values); err != nil {
+		return nil, err
+	}
+	overridden := DefaultConfig()
+	if err := json.Unmarshal(data, &overridden); err != nil {
+		return nil, err
+	}
+	if err := overridden.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", strings.Join(applied, ", "), err)
+	}
+	*cfg = overridden
+	sort.Strings(applied)
+	return applied, nil
+}
+
+// applyEnvironmentValues replaces the values of the decoded configuration object that are set in
+// the environment, where path holds the keys leading to the object
+func applyEnvironmentValues(values map[string]interface{}, path []string) ([]string, error) {
+	var applied []string
+	for key, value := range values {
+		keyPath := append(append([]string(nil), path...), key)
+		if object, ok := value.(map[string]interface{}); ok {
+			names, err := applyEnvironmentValues(object, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			applied = append(applied, names...)
+			continue
+		}
+		name := envName(keyPath...)
+		setting, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		t, err := configKeyType(strings.Join(keyPath, "."))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		parsed, err := parseEnvironmentValue(t, setting)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		values[key] = parsed
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
+
+// parseEnvironmentValue parses the value of an environment variable as the type of the key in the
+// configuration, so that an empty or missing list still gets items of the right type
+func parseEnvironmentValue(t reflect.Type, setting string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Float64, reflect.Int:
+		n, err := strconv.ParseFloat(setting, 64)
+		if err != nil || t.Kind() == reflect.Int && n != float64(int(n)) {
+			return nil, fmt.Errorf("expected a number, got %q", setting)
+		}
+		return n, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(setting)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", setting)
+		}
+		return b, nil
+	case reflect.Slice:
+		if elem := t.Elem().Kind(); t == rawMessageType || elem != reflect.String && elem != reflect.Float64 {
+			return nil, fmt.Errorf("lists of objects cannot be set from the environment")
+		}
+		list := []interface{}{}
+		if setting == "" {
+			return list, nil
+		}
+		for _, item := range strings.Split(setting, ",") {
+			value, err := parseEnvironmentValue(t.Elem(), strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	}
+	return setting, nil
+}
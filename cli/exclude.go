@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreFileName is the file listing exclude patterns at the root of an analysis
+const ignoreFileName = ".zedsignore"
+
+// readIgnoreFile returns the patterns in an ignore file, skipping blank lines and # comments.
+// A missing file yields no patterns.
+func readIgnoreFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// readExcludes returns the exclude patterns of an analysis rooted at root: those of its ignore
+// file, followed by the given patterns
+func readExcludes(root string, patterns []string) ([]string, error) {
+	excludes, err := readIgnoreFile(filepath.Join(root, ignoreFileName))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", ignoreFileName, err)
+	}
+	return append(excludes, patterns...), nil
+}
+
+// excludedFile reports whether a file of an analysis rooted at root matches an exclude pattern
+func excludedFile(root, path string, patterns []string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && isExcluded(filepath.ToSlash(rel), patterns)
+}
+
+// isExcluded reports whether a slash-separated path relative to the analysis root matches an
+// exclude pattern. Patterns follow a subset of .gitignore: a pattern without a slash matches any
+// path element, a pattern with a slash matches from the root (where "**" matches any directories),
+// and a trailing slash restricts the pattern to directories.
+func isExcluded(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchesPattern(rel, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPattern reports whether a slash-separated relative path matches an exclude pattern
+func matchesPattern(rel, pattern string) bool {
+	segments := strings.Split(rel, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	// A directory pattern may match any element but the file name itself.
+	limit := len(segments)
+	if dirOnly {
+		limit--
+	}
+	if !strings.Contains(pattern, "/") {
+		for _, segment := range segments[:limit] {
+			if ok, _ := filepath.Match(pattern, segment); ok {
+				return true
+			}
+		}
+		return false
+	}
+	patternSegments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	for n := 1; n <= limit; n++ {
+		if ok, _ := matchSegments(patternSegments, segments[:n]); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// metricExplanation describes how a function metric is computed. The summary is the one-line
+// definition that also comments the metric in the file written by `zeds init`, and the details
+// follow it in `zeds explain`.
+type metricExplanation struct {
+	key     string
+	summary string
+	details string
+	// breakdown prints the intermediate values behind the metric of a function, or is nil when
+	// the metric has none worth showing
+	breakdown func(fn explainedFunction, cfg *Config)
+}
+
+// description returns the full description of the metric
+func (e metricExplanation) description() string {
+	return e.summary + " " + e.details
+}
+
+// explainedFunction is a function whose metrics are explained, with its syntax tree for the breakdowns
+type explainedFunction struct {
+	result analyzer.MethodResult
+	decl   *ast.FuncDecl
+	fset   *token.FileSet
+	// body is the source of the function body, from its opening to its closing brace
+	body string
+}
+
+// metricExplanations lists the metrics accepted by the explain command: the metrics with thresholds
+// and the Halstead volume
+var metricExplanations = []metricExplanation{
+	{key: "cyclomatic", breakdown: explainCyclomatic,
+		summary: "The number of independent paths through the function.",
+		details: "It is 1, plus one for every if, for and range statement, every case and default clause of a switch or type switch, and every && and || operator. Function literals in the body are included. The clauses of select statements are not counted."},
+	{key: "npath",
+		summary: "The number of acyclic execution paths through the function.",
+		details: "Sequential statements multiply their path counts, while branches add them, so the count grows exponentially with consecutive decisions. Each && and || in a condition adds a path. Loops count as either skipping or running their body once, and function literals count as plain statements."},
+	{key: "abc", breakdown: explainABC,
+		summary: "The size of the function from its assignments, branches (calls) and conditions, as sqrt(A² + B² + C²).",
+		details: "Function literals are included. Assignments count every non-blank name assigned by =, :=, an assignment operator, ++, --, an initialized var declaration or a range clause. Branches count function calls. Conditions count comparison operators, else branches and the clauses of switch and select statements. Unlike LOC, it does not change with formatting."},
+	{key: "nesting",
+		summary: "The deepest nesting of control structures in the function.",
+		details: "It counts if, for, range, switch, type switch and select statements and function literals. Statements directly in the body are at depth 0. An else if continues its chain at the depth of the first if."},
+	{key: "maintainabilityIndex", breakdown: explainMaintainabilityIndex,
+		summary: "A combination of the Halstead volume, the cyclomatic complexity and the lines of code of the function, where lower is worse.",
+		details: "The formula is 171 - 5.2·ln(V) - 0.23·CC - 16.2·ln(LOC) for the volume V, the cyclomatic complexity CC and the lines of code LOC. The zeds variant rescales it to 0-100 and adds a bonus of commentDensityMultiplier·sin(√(2.4·CM)) for the comment density CM of the function, counting its doc comment. The miVariant setting selects the original SEI formula or the Visual Studio formula instead."},
+	{key: "loc",
+		summary: "The number of lines of the function body.",
+		details: "Lines are counted from its opening to its closing brace, including comments and blank lines."},
+	{key: "lloc",
+		summary: "The number of statements of the function, including those of its function literals.",
+		details: "Blocks, empty statements, labels and case clauses are not counted, so the figure does not depend on formatting."},
+	{key: "indentation",
+		summary: "The sum of the indentation levels of the non-blank lines of the function body.",
+		details: "Levels are relative to its closing brace. A level is tabWidth columns, so a tab is one level, as are tabWidth spaces. It only looks at whitespace, so it serves as a proxy for structural complexity."},
+	{key: "localVars",
+		summary: "The number of local variables of the function.",
+		details: "Variables are declared by var declarations, short variable declarations and range clauses. Names that a short variable declaration merely reassigns are not counted, while shadowing in an inner scope is."},
+	{key: "params",
+		summary: "The number of parameters of the function.",
+		details: "Each name of a grouped parameter such as (a, b int) counts separately. The receiver is not counted."},
+	{key: "results",
+		summary: "The number of return values of the function.",
+		details: "Each name of grouped named results counts separately."},
+	{key: "exits",
+		summary: "The number of return, break, continue and goto statements and panic calls of the function.",
+		details: "Function literals are skipped, since their exits leave only the literal."},
+	{key: "magicNumbers",
+		summary: "The number of integer and floating-point literals of the function whose meaning is not named.",
+		details: "Literals in const declarations are not counted. A negated literal such as -1 counts as one number. Numbers in the magicNumbers allowlist of the configuration are not counted."},
+	{key: "errorDensity", breakdown: explainErrorDensity,
+		summary: "The share of the lines of code of the function taken by error checks.",
+		details: "An error check is an if statement comparing an error variable with nil, from the if to the closing brace of its body. Error variables are recognized by name, err or a name ending in Err, since the analysis has no type information."},
+	{key: "fanOut", breakdown: explainFanOut,
+		summary: "The number of distinct functions and methods the function calls.",
+		details: "Calls in function literals are included. Callees are resolved with type information, so calls to the same function through different names count once. Builtins, type conversions and calls of function values are not counted."},
+	{key: "concurrency", breakdown: explainConcurrency,
+		summary: "The concurrency weight of the function, from its goroutines, channel operations, selects and mutex operations.",
+		details: "It is the sum of the go statements, channel operations (sends, receives and close calls), select statements and mutex operations (calls to methods named like those of sync.Mutex and sync.RWMutex), including those of function literals."},
+	{key: "goroutines",
+		summary: "The number of go statements of the function.",
+		details: "Those in function literals are included."},
+	{key: "defers",
+		summary: "The number of defer statements of the function.",
+		details: "Those in function literals are included."},
+	{key: "halstead", breakdown: explainHalstead,
+		summary: "The Halstead volume N·log2(n) of the function body.",
+		details: "The length N is the total number of operators and operands in the function body and the vocabulary n is the number of distinct ones. Operands are identifiers and literals. Which tokens are operators depends on the halsteadProfile setting: classic counts arithmetic, bitwise, comparison, logical and assignment operators, go-keywords adds Go keywords, and extended also adds delimiters and function calls."},
+}
+
+// findMetricExplanation returns the explanation of the metric with the given key
+func findMetricExplanation(key string) (metricExplanation, bool) {
+	for _, explanation := range metricExplanations {
+		if explanation.key == key {
+			return explanation, true
+		}
+	}
+	return metricExplanation{}, false
+}
+
+// title returns the name of the metric as shown in reports
+func (e metricExplanation) title() string {
+	if metric, ok := findThresholdMetric(e.key); ok {
+		return metric.title
+	}
+	return "Halstead volume"
+}
+
+// handleExplainCommand describes how a metric is computed and, given a function, shows the
+// intermediate values behind its value
+func handleExplainCommand(args []string) {
+	var filePath string
+	flags := newFlagSet("explain", "<metric>", "<metric> <function> -f <file>")
+	flags.stringVar(&filePath, "file", "f", "analyze the function in the Go `file`")
+	positional := parseCommandArgs(flags, args[1:])
+	if len(positional) == 0 || len(positional) > 2 {
+		failUsage(flags, fmt.Errorf("expected a metric and an optional function"))
+	}
+	explanation, ok := findMetricExplanation(positional[0])
+	if !ok {
+		var keys []string
+		for _, e := range metricExplanations {
+			keys = append(keys, e.key)
+		}
+		failUsage(flags, fmt.Errorf("unknown metric '%s'. Valid metrics: %s", positional[0], strings.Join(keys, ", ")))
+	}
+	if len(positional) == 2 && filePath == "" {
+		failUsage(flags, fmt.Errorf("no file specified for function '%s'", positional[1]))
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	// The thresholds and analyzer options of a file are those analyze uses for it.
+	if filePath != "" {
+		cfg = cfg.ForFile(filePath)
+	}
+	fmt.Fprintln(stdout, Bold+ColorCyan+explanation.title()+" ("+explanation.key+")"+ColorReset)
+	fmt.Fprintln(stdout, explanation.description())
+	if metric, ok := findThresholdMetric(explanation.key); ok {
+		medium, high := metric.limits(cfg)
+		below := ""
+		if metric.lowerIsWorse {
+			below = "below "
+		}
+		fmt.Fprintf(stdout, "Thresholds: medium %s%.*f, high %s%.*f\n", below, metric.precision, medium, below, metric.precision, high)
+	}
+	if len(positional) < 2 {
+		return
+	}
+
+	fn, err := findExplainedFunction(filePath, positional[1], cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintf(stdout, "Function: %s%s%s (%s:%d)\n", ColorCyan, fn.result.QualifiedName(), ColorReset, filePath, fn.result.Line)
+	if metric, ok := findThresholdMetric(explanation.key); ok {
+		fmt.Fprintf(stdout, "%s: %s%.*f%s\n", metric.title, metric.color(fn.result, cfg), metric.precision, metric.value(fn.result), ColorReset)
+	} else {
+		fmt.Fprintf(stdout, "%s: %.2f\n", explanation.title(), fn.result.HalsteadVolume)
+	}
+	if explanation.breakdown != nil {
+		explanation.breakdown(fn, cfg)
+	}
+}
+
+// findExplainedFunction analyzes the file and returns the function with the given name, which is
+// qualified with its receiver type for methods, e.g. Parser.Parse
+func findExplainedFunction(path, name string, cfg *Config) (explainedFunction, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return explainedFunction{}, err
+	}
+	results, _, err := analyzer.AnalyzeSource(path, src, cfg.AnalyzerOptions())
+	if err != nil {
+		return explainedFunction{}, err
+	}
+	classifyResults(results, cfg)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return explainedFunction{}, err
+	}
+	for _, res := range results {
+		if res.QualifiedName() != name {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil && fset.Position(fn.Pos()).Line == res.Line {
+				body := string(src[fset.Position(fn.Body.Pos()).Offset:fset.Position(fn.Body.End()).Offset])
+				return explainedFunction{result: res, decl: fn, fset: fset, body: body}, nil
+			}
+		}
+	}
+	return explainedFunction{}, fmt.Errorf("no function named '%s' in %s", name, path)
+}
+
+// explainCyclomatic lists the decision points of the function with their lines
+func explainCyclomatic(fn explainedFunction, cfg *Config) {
+	points := analyzer.FindDecisionPoints(fn.fset, fn.decl.Body)
+	fmt.Fprintln(stdout, "  1        base path")
+	for _, point := range points {
+		fmt.Fprintf(stdout, "  +1 %-8s line %d\n", point.Kind, point.Line)
+	}
+	fmt.Fprintf(stdout, "  = %d (1 + %d decision points)\n", len(points)+1, len(points))
+}
+
+// explainABC shows the assignment, branch and condition counts behind the ABC size
+func explainABC(fn explainedFunction, cfg *Config) {
+	abc := fn.result.ABC
+	fmt.Fprintf(stdout, "  Assignments A = %d\n  Branches    B = %d\n  Conditions  C = %d\n", abc.Assignments, abc.Branches, abc.Conditions)
+	fmt.Fprintf(stdout, "  = sqrt(%d² + %d² + %d²) = %.2f\n", abc.Assignments, abc.Branches, abc.Conditions, abc.Score)
+}
+
+// explainMaintainabilityIndex shows the terms of the maintainability index formula
+func explainMaintainabilityIndex(fn explainedFunction, cfg *Config) {
+	res := fn.result
+	volume := math.Max(res.HalsteadVolume, 1)
+	loc := math.Max(float64(res.LOC), 1)
+	base := 171 - 5.2*math.Log(volume) - 0.23*float64(res.Cyclomatic) - 16.2*math.Log(loc)
+	commentTerm := math.Sin(math.Sqrt(2.4 * res.CommentDensity))
+	fmt.Fprintf(stdout, "  V = %.2f, CC = %d, LOC = %d, CM = %.2f\n", res.HalsteadVolume, res.Cyclomatic, res.LOC, res.CommentDensity)
+	fmt.Fprintf(stdout, "  base = 171 - 5.2·ln(%.2f) - 0.23·%d - 16.2·ln(%.0f) = 171 - %.2f - %.2f - %.2f = %.2f\n",
+		volume, res.Cyclomatic, loc, 5.2*math.Log(volume), 0.23*float64(res.Cyclomatic), 16.2*math.Log(loc), base)
+	switch cfg.MIVariant {
+	case analyzer.MIOriginal:
+		fmt.Fprintf(stdout, "  MI (original) = base + 50·sin(√(2.4·CM)) = %.2f + 50·%.2f = %.2f\n", base, commentTerm, res.MaintainabilityIndex)
+	case analyzer.MIVisualStudio:
+		fmt.Fprintf(stdout, "  MI (visualstudio) = max(0, base·100/171) = %.2f\n", res.MaintainabilityIndex)
+	default:
+		fmt.Fprintf(stdout, "  MI (zeds) = max(0, base·100/171 + %.2f·sin(√(2.4·CM))) = max(0, %.2f + %.2f·%.2f) = %.2f\n",
+			cfg.CommentDensityMultiplier, base*100/171, cfg.CommentDensityMultiplier, commentTerm, res.MaintainabilityIndex)
+	}
+}
+
+// explainErrorDensity shows the error checks behind the error handling density
+func explainErrorDensity(fn explainedFunction, cfg *Config) {
+	e := fn.result.ErrorHandling
+	fmt.Fprintf(stdout, "  %d error check(s) over %d line(s), %d call(s) assigning an error\n", e.Checks, e.CheckLines, e.Calls)
+	fmt.Fprintf(stdout, "  = %d check lines / %d LOC = %.2f\n", e.CheckLines, fn.result.LOC, e.Density)
+}
+
+// explainFanOut notes that fan-out needs the type information of a package
+func explainFanOut(fn explainedFunction, cfg *Config) {
+	fmt.Fprintln(stdout, Italic+ColorYellow+"  Fan-out needs type information and is only computed when analyzing packages with -p."+ItalicReset+ColorReset)
+}
+
+// explainConcurrency shows the concurrency primitives behind the concurrency weight
+func explainConcurrency(fn explainedFunction, cfg *Config) {
+	c := fn.result.Concurrency
+	fmt.Fprintf(stdout, "  = %d go statement(s) + %d channel operation(s) + %d select(s) + %d mutex operation(s) = %d\n",
+		c.Goroutines, c.ChannelOps, c.Selects, c.MutexOps, c.Weight)
+}
+
+// explainHalstead lists the operators and operands counted in the function body
+func explainHalstead(fn explainedFunction, cfg *Config) {
+	operators, operands := analyzer.CountHalsteadTokens(fn.body, cfg.HalsteadProfile)
+	h := fn.result.Halstead
+	fmt.Fprintf(stdout, "  Operators (n1 = %d distinct, N1 = %d total): %s\n", h.DistinctOperators, h.TotalOperators, formatTokenCounts(operators))
+	fmt.Fprintf(stdout, "  Operands (n2 = %d distinct, N2 = %d total): %s\n", h.DistinctOperands, h.TotalOperands, formatTokenCounts(operands))
+	fmt.Fprintf(stdout, "  n = n1 + n2 = %d, N = N1 + N2 = %d\n", h.Vocabulary, h.Length)
+	fmt.Fprintf(stdout, "  = N·log2(n) = %d·log2(%d) = %.2f\n", h.Length, h.Vocabulary, h.Volume)
+}
+
+// formatTokenCounts lists tokens with their counts, most frequent first, e.g. "err×3, :=×2"
+func formatTokenCounts(counts map[string]int) string {
+	tokens := make([]string, 0, len(counts))
+	for tok := range counts {
+		tokens = append(tokens, tok)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if counts[tokens[i]] != counts[tokens[j]] {
+			return counts[tokens[i]] > counts[tokens[j]]
+		}
+		return tokens[i] < tokens[j]
+	})
+	parts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		parts[i] = fmt.Sprintf("%s×%d", tok, counts[tok])
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/parser"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// flagSet parses the options of a command. It wraps a flag.FlagSet with the conventions of GNU
+// tools: options may come before or after the positional arguments, a long option may have a
+// one-letter alias such as -r for --recursive, one-letter boolean options may be combined as in
+// -rv, and the usage is generated from the defined options. As with the flag package, an option
+// takes one or two dashes, a value follows as the next argument or after "=", and "--" ends the
+// options.
+type flagSet struct {
+	fs *flag.FlagSet
+	// synopses are the forms of the command line, without the program and command names
+	synopses []string
+	// names lists the options in definition order, without their aliases
+	names   []string
+	aliases map[string]string
+	// stopAtArgument ends the options at the first positional argument
+	stopAtArgument bool
+}
+
+// newFlagSet creates the option parser of a command with the given forms of its command line
+func newFlagSet(command string, synopses ...string) *flagSet {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return &flagSet{fs: fs, synopses: synopses, aliases: make(map[string]string)}
+}
+
+// define registers an option under its name and optional one-letter alias. A value placeholder
+// can be given in the usage between backquotes, e.g. "read the `file`".
+func (f *flagSet) define(value flag.Value, name, alias, usage string) {
+	f.fs.Var(value, name, usage)
+	f.names = append(f.names, name)
+	if alias != "" {
+		f.fs.Var(value, alias, usage)
+		f.aliases[name] = alias
+	}
+}
+
+// boolVar defines a boolean option
+func (f *flagSet) boolVar(p *bool, name, alias, usage string) {
+	f.define((*boolValue)(p), name, alias, usage)
+}
+
+// stringVar defines an option with a string value
+func (f *flagSet) stringVar(p *string, name, alias, usage string) {
+	f.define((*stringValue)(p), name, alias, usage)
+}
+
+// intVar defines an option with an integer value
+func (f *flagSet) intVar(p *int, name, alias, usage string) {
+	f.define((*intValue)(p), name, alias, usage)
+}
+
+// optionalFloatVar defines an option with a number value, which is stored in a new float64 so
+// that *p stays nil when the option is not given
+func (f *flagSet) optionalFloatVar(p **float64, name, alias, usage string) {
+	f.define(optionalFloatValue{p}, name, alias, usage)
+}
+
+// listVar defines an option that can be repeated, collecting its values. With a separator, each
+// value is also split, so that --fail-on a,b equals --fail-on a --fail-on b.
+func (f *flagSet) listVar(p *[]string, name, alias, separator, usage string) {
+	f.define(&listValue{list: p, separator: separator}, name, alias, usage)
+}
+
+// parse parses the arguments, which do not include the command name, and returns the positional
+// arguments. It returns flag.ErrHelp when -h or --help is given.
+func (f *flagSet) parse(args []string) ([]string, error) {
+	args = f.expandCombined(args)
+	var positional []string
+	for {
+		if err := f.fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := f.fs.Args()
+		consumed := len(args) - len(rest)
+		if len(rest) == 0 || f.stopAtArgument || consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// expandCombined splits combined one-letter boolean options such as -rv into -r -v
+func (f *flagSet) expandCombined(args []string) []string {
+	var expanded []string
+	for i, arg := range args {
+		if arg == "--" {
+			return append(expanded, args[i:]...)
+		}
+		if !f.isCombined(arg) {
+			expanded = append(expanded, arg)
+			continue
+		}
+		for _, letter := range arg[1:] {
+			expanded = append(expanded, "-"+string(letter))
+		}
+	}
+	return expanded
+}
+
+// isCombined reports whether an argument is a group of one-letter boolean options
+func (f *flagSet) isCombined(arg string) bool {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' || f.fs.Lookup(arg[1:]) != nil {
+		return false
+	}
+	for _, letter := range arg[1:] {
+		option := f.fs.Lookup(string(letter))
+		if option == nil {
+			return false
+		}
+		if b, ok := option.Value.(interface{ IsBoolFlag() bool }); !ok || !b.IsBoolFlag() {
+			return false
+		}
+	}
+	return true
+}
+
+// usage returns the usage of the command, listing its forms and options
+func (f *flagSet) usage() string {
+	var b strings.Builder
+	b.WriteString("Usage:")
+	for _, synopsis := range f.synopses {
+		b.WriteString("\n  zeds " + f.fs.Name())
+		if synopsis != "" {
+			b.WriteString(" " + synopsis)
+		}
+	}
+	if len(f.names) == 0 {
+		return b.String()
+	}
+	b.WriteString("\nOptions:")
+	for _, name := range f.names {
+		option := f.fs.Lookup(name)
+		placeholder, usage := flag.UnquoteUsage(option)
+		names := optionName(name)
+		if alias, ok := f.aliases[name]; ok {
+			names = optionName(alias) + ", " + names
+		}
+		if placeholder != "" {
+			names += " <" + placeholder + ">"
+		}
+		if option.DefValue != "" && option.DefValue != "false" && option.DefValue != "0" {
+			usage += " (default " + option.DefValue + ")"
+		}
+		fmt.Fprintf(&b, "\n  %-28s %s", names, usage)
+	}
+	return b.String()
+}
+
+// optionName returns how an option is written: with one dash for one letter, two otherwise
+func optionName(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// applyEnvironment sets the options that are not given on the command line from their ZEDS_
+// environment variables, e.g. ZEDS_FAIL_ON for --fail-on, so that the command line overrides the
+// environment. The values of repeatable options are separated by commas. When names are given,
+// only those options are read from the environment.
+func (f *flagSet) applyEnvironment(names ...string) error {
+	given := make(map[string]bool)
+	f.fs.Visit(func(option *flag.Flag) { given[option.Name] = true })
+	if len(names) == 0 {
+		names = f.names
+	}
+	for _, name := range names {
+		if given[name] || given[f.aliases[name]] {
+			continue
+		}
+		setting, ok := os.LookupEnv(envName(name))
+		if !ok {
+			continue
+		}
+		option := f.fs.Lookup(name)
+		values := []string{setting}
+		if _, ok := option.Value.(*listValue); ok {
+			values = strings.Split(setting, ",")
+		}
+		for _, value := range values {
+			if err := option.Value.Set(value); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %v", setting, envName(name), err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseCommandArgs parses the arguments of a command. On -h or --help it prints the usage and
+// exits successfully; on an invalid option it prints the error and the usage and exits with an error.
+func parseCommandArgs(f *flagSet, args []string) []string {
+	positional, err := f.parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(stdout, f.usage())
+		exit(0)
+	}
+	if err != nil {
+		failUsage(f, err)
+	}
+	return positional
+}
+
+// parseCommandOptions parses the arguments of a command that takes no positional arguments
+func parseCommandOptions(f *flagSet, args []string) {
+	if positional := parseCommandArgs(f, args); len(positional) > 0 {
+		failUsage(f, fmt.Errorf("unexpected argument '%s'", positional[0]))
+	}
+}
+
+// failUsage prints an error about the command line of a command followed by its usage, and exits
+func failUsage(f *flagSet, err error) {
+	fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+	fmt.Fprintln(stderr, ColorRed+f.usage()+ColorReset)
+	exit(exitError)
+}
+
+// boolValue is a boolean option
+type boolValue bool
+
+func (b *boolValue) Set(s string) error {
+	switch s {
+	case "true":
+		*b = true
+	case "false":
+		*b = false
+	default:
+		return fmt.Errorf("expected true or false")
+	}
+	return nil
+}
+
+func (b *boolValue) String() string { return fmt.Sprint(bool(*b)) }
+
+func (b *boolValue) IsBoolFlag() bool { return true }
+
+// stringValue is an option with a string value
+type stringValue string
+
+func (s *stringValue) Set(value string) error {
+	*s = stringValue(value)
+	return nil
+}
+
+func (s *stringValue) String() string { return string(*s) }
+
+// intValue is an option with an integer value
+type intValue int
+
+func (i *intValue) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("expected an integer")
+	}
+	*i = intValue(n)
+	return nil
+}
+
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+// optionalFloatValue is an option with a number value that is nil until the option is given
+type optionalFloatValue struct {
+	p **float64
+}
+
+func (o optionalFloatValue) Set(value string) error {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("expected a number")
+	}
+	*o.p = &n
+	return nil
+}
+
+func (o optionalFloatValue) String() string {
+	if o.p == nil || *o.p == nil {
+		return ""
+	}
+	return strconv.FormatFloat(**o.p, 'f', -1, 64)
+}
+
+// listValue is a repeatable option that collects its values
+type listValue struct {
+	list      *[]string
+	separator string
+}
+
+func (l *listValue) Set(value string) error {
+	if l.separator == "" {
+		*l.list = append(*l.list, value)
+	} else {
+		*l.list = append(*l.list, strings.Split(value, l.separator)...)
+	}
+	return nil
+}
+
+func (l *listValue) String() string {
+	if l.list == nil {
+		return ""
+	}
+	return strings.Join(*l.list, ",")
+}
+
+// regexpValue is an option whose value is a regular expression
+type regexpValue struct {
+	re **regexp.Regexp
+}
+
+func (r regexpValue) Set(value string) error {
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return err
+	}
+	*r.re = re
+	return nil
+}
+
+func (r regexpValue) String() string {
+	if r.re == nil || *r.re == nil {
+		return ""
+	}
+	return (*r.re).String()
+}
+
+// parserModeValue is a boolean option that adds a flag to a parser mode
+type parserModeValue struct {
+	mode *parser.Mode
+	flag parser.Mode
+}
+
+func (p parserModeValue) Set(value string) error {
+	switch value {
+	case "true":
+		*p.mode |= p.flag
+	case "false":
+		*p.mode &^= p.flag
+	default:
+		return fmt.Errorf("expected true or false")
+	}
+	return nil
+}
+
+func (p parserModeValue) String() string {
+	if p.mode == nil {
+		return "false"
+	}
+	return fmt.Sprint(*p.mode&p.flag != 0)
+}
+
+func (p parserModeValue) IsBoolFlag() bool { return true }
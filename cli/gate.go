@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// gateCondition is the outcome of one condition of the quality gate
+type gateCondition struct {
+	passed bool
+	// description states the condition and the value it was checked against
+	description string
+}
+
+// EvaluateQualityGate checks the analyzed files against the conditions of the quality gate.
+// It returns nothing when the gate is disabled.
+func EvaluateQualityGate(files []fileResults, cfg *Config) []gateCondition {
+	gate := cfg.QualityGate
+	if !gate.Enabled {
+		return nil
+	}
+	var all []analyzer.MethodResult
+	for _, f := range files {
+		all = append(all, f.results...)
+	}
+	var conditions []gateCondition
+	if gate.MaxCyclomatic > 0 {
+		conditions = append(conditions, maxCyclomaticCondition(files, gate.MaxCyclomatic))
+	}
+	if gate.MinAverageMI > 0 && len(all) > 0 {
+		mi := analyzer.Summarize(all).AverageMI
+		conditions = append(conditions, gateCondition{
+			passed:      mi >= gate.MinAverageMI,
+			description: fmt.Sprintf("average maintainability index is %.2f (minimum %.2f)", mi, gate.MinAverageMI),
+		})
+	}
+	if gate.MaxHighViolationsPerPackage > 0 {
+		conditions = append(conditions, highViolationsCondition(files, gate.MaxHighViolationsPerPackage, cfg))
+	}
+	return conditions
+}
+
+// maxCyclomaticCondition checks that no function has a cyclomatic complexity above the limit
+func maxCyclomaticCondition(files []fileResults, limit int) gateCondition {
+	worst, worstFile := analyzer.MethodResult{}, ""
+	for _, f := range files {
+		for _, res := range f.results {
+			if res.Cyclomatic > worst.Cyclomatic {
+				worst, worstFile = res, f.file
+			}
+		}
+	}
+	if worstFile == "" {
+		return gateCondition{passed: true, description: fmt.Sprintf("no functions (maximum cyclomatic complexity %d)", limit)}
+	}
+	return gateCondition{
+		passed: worst.Cyclomatic <= limit,
+		description: fmt.Sprintf("highest cyclomatic complexity is %d, in %s at %s:%d (maximum %d)",
+			worst.Cyclomatic, worst.QualifiedName(), reportURI(worstFile), worst.Line, limit),
+	}
+}
+
+// highViolationsCondition checks that no package, i.e. directory, has more high threshold violations than the limit
+func highViolationsCondition(files []fileResults, limit int, cfg *Config) gateCondition {
+	counts := make(map[string]int)
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			if v.severity == SeverityRed {
+				counts[filepath.Dir(f.file)]++
+			}
+		}
+	}
+	worst, worstDir := 0, ""
+	for dir, count := range counts {
+		if count > worst || count == worst && dir < worstDir {
+			worst, worstDir = count, dir
+		}
+	}
+	if worstDir == "" {
+		return gateCondition{passed: true, description: fmt.Sprintf("no high threshold violations (maximum %d per package)", limit)}
+	}
+	return gateCondition{
+		passed: worst <= limit,
+		description: fmt.Sprintf("most high threshold violations in a package is %d, in %s (maximum %d)",
+			worst, reportURI(worstDir), limit),
+	}
+}
+
+// gatePassed reports whether every condition of the quality gate passed
+func gatePassed(conditions []gateCondition) bool {
+	for _, c := range conditions {
+		if !c.passed {
+			return false
+		}
+	}
+	return true
+}
+
+// printQualityGate prints each condition of the quality gate and the verdict
+func printQualityGate(w io.Writer, conditions []gateCondition) {
+	if len(conditions) == 0 {
+		return
+	}
+	fmt.Fprintln(w, ColorCyan+"Quality Gate:"+ColorReset)
+	fmt.Fprintln(w, ColorCyan+"------------------------------------------"+ColorReset)
+	for _, c := range conditions {
+		if c.passed {
+			fmt.Fprintln(w, "  "+ColorGreen+"✔ "+ColorReset+c.description)
+		} else {
+			fmt.Fprintln(w, "  "+ColorRed+"✘ "+ColorReset+c.description)
+		}
+	}
+	if gatePassed(conditions) {
+		fmt.Fprintln(w, Bold+ColorGreen+"Quality gate passed."+ColorReset)
+	} else {
+		fmt.Fprintln(w, Bold+ColorRed+"Quality gate failed."+ColorReset)
+	}
+	fmt.Fprintln(w)
+}
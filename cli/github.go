@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// githubDataEscaper escapes the message of a GitHub Actions workflow command
+var githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// githubPropertyEscaper escapes the property values of a GitHub Actions workflow command
+var githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+// buildGitHubAnnotations writes each threshold violation as a GitHub Actions workflow command,
+// e.g. "::warning file=main.go,line=12,endLine=40,title=zeds/loc-medium::...". High violations
+// are reported as errors and medium ones as warnings, like in the SARIF report.
+func buildGitHubAnnotations(files []fileResults, cfg *Config) string {
+	// GitHub resolves the file of an annotation from the root of the repository, which is not
+	// necessarily the working directory. Outside a repository, paths stay relative to the latter.
+	root, _ := gitRoot()
+	var sb strings.Builder
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			fmt.Fprintf(&sb, "::%s file=%s,line=%d,endLine=%d,title=%s::%s\n", sarifLevel(v.severity),
+				githubPropertyEscaper.Replace(annotationPath(v.file, root)), v.function.Line, v.function.EndLine,
+				githubPropertyEscaper.Replace(v.ruleID()), githubDataEscaper.Replace(v.message()))
+		}
+	}
+	return sb.String()
+}
+
+// annotationPath returns the path of a file relative to the repository root, with forward
+// slashes. Files outside the root, or any file when root is empty, fall back to reportURI.
+func annotationPath(file, root string) string {
+	if root == "" {
+		return reportURI(file)
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return reportURI(file)
+	}
+	// git reports the root with symbolic links resolved, e.g. /private/tmp for /tmp on macOS.
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return reportURI(file)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// codeQualityIssue is an issue of the GitLab Code Quality report, a subset of the Code Climate issue format
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// codeQualitySeverity maps a violation severity to a Code Quality severity
+func codeQualitySeverity(severity Severity) string {
+	if severity == SeverityRed {
+		return "major"
+	}
+	return "minor"
+}
+
+// violationFingerprint identifies a violation by its rule, file and function. Line numbers are left
+// out so that the issue keeps its identity when code above the function moves.
+func violationFingerprint(v violation) string {
+	sum := sha256.Sum256([]byte(v.ruleID() + "\x00" + reportURI(v.file) + "\x00" + v.function.QualifiedName()))
+	return hex.EncodeToString(sum[:])
+}
+
+// newCodeQualityIssue converts a threshold violation into a Code Quality issue
+func newCodeQualityIssue(v violation) codeQualityIssue {
+	return codeQualityIssue{
+		Description: v.message(),
+		CheckName:   v.ruleID(),
+		Fingerprint: violationFingerprint(v),
+		Severity:    codeQualitySeverity(v.severity),
+		Location: codeQualityLocation{
+			Path:  reportURI(v.file),
+			Lines: codeQualityLines{Begin: v.function.Line, End: v.function.EndLine},
+		},
+	}
+}
+
+// buildGitLab maps the threshold violations of the analyzed files to a GitLab Code Quality report
+func buildGitLab(files []fileResults, cfg *Config) []codeQualityIssue {
+	issues := []codeQualityIssue{}
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			issues = append(issues, newCodeQualityIssue(v))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// expandFilePatterns expands the -f arguments into a sorted list of files without duplicates.
+// Arguments without glob characters are used as is, and "**" matches any number of directories.
+func expandFilePatterns(patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			if matches, err = globPattern(pattern); err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match the pattern '%s'", pattern)
+			}
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// globPattern returns the files matching a glob pattern that may contain "**"
+func globPattern(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(pattern)
+	}
+
+	// Walk from the longest directory prefix without glob characters.
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	root := 0
+	for root < len(segments) && !strings.ContainsAny(segments[root], "*?[") {
+		root++
+	}
+	dir := filepath.FromSlash(strings.Join(segments[:root], "/"))
+	if dir == "" {
+		dir = "."
+	}
+
+	var matches []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Like ./..., "**" does not descend into the directories the go tool ignores, unless the
+		// pattern names them.
+		if entry.IsDir() {
+			if path != dir && ignoredDir(entry.Name()) && !containsString(segments[root:], entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		ok, err := matchSegments(segments[root:], strings.Split(filepath.ToSlash(rel), "/"))
+		if ok {
+			matches = append(matches, path)
+		}
+		return err
+	})
+	return matches, err
+}
+
+// matchSegments matches path segments against pattern segments, where a "**" segment
+// matches zero or more path segments and other segments follow filepath.Match
+func matchSegments(pattern, path []string) (bool, error) {
+	if len(pattern) == 0 {
+		return len(path) == 0, nil
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if ok, err := matchSegments(pattern[1:], path[i:]); ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	if len(path) == 0 {
+		return false, nil
+	}
+	ok, err := filepath.Match(pattern[0], path[0])
+	if !ok || err != nil {
+		return false, err
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
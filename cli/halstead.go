@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// halsteadMeasure is a derived Halstead measure that the configuration can show next to the volume
+type halsteadMeasure struct {
+	// key is the measure's name in the halsteadMeasures setting
+	key   string
+	title string
+	value func(analyzer.HalsteadMetrics) float64
+}
+
+// halsteadMeasures lists the derived Halstead measures that can be shown next to the volume
+var halsteadMeasures = []halsteadMeasure{
+	{"difficulty", "Halstead Difficulty", func(h analyzer.HalsteadMetrics) float64 { return h.Difficulty }},
+	{"effort", "Halstead Effort", func(h analyzer.HalsteadMetrics) float64 { return h.Effort }},
+	{"level", "Halstead Level", func(h analyzer.HalsteadMetrics) float64 { return h.Level }},
+	{"languageLevel", "Halstead Language Level", func(h analyzer.HalsteadMetrics) float64 { return h.LanguageLevel }},
+	{"intelligenceContent", "Halstead Intelligence Content", func(h analyzer.HalsteadMetrics) float64 { return h.IntelligenceContent }},
+}
+
+// findHalsteadMeasure returns the derived Halstead measure with the given key
+func findHalsteadMeasure(key string) (halsteadMeasure, bool) {
+	for _, measure := range halsteadMeasures {
+		if measure.key == key {
+			return measure, true
+		}
+	}
+	return halsteadMeasure{}, false
+}
+
+// halsteadMeasureKeys lists the keys of the derived Halstead measures
+func halsteadMeasureKeys() string {
+	keys := make([]string, len(halsteadMeasures))
+	for i, measure := range halsteadMeasures {
+		keys[i] = measure.key
+	}
+	return strings.Join(keys, ", ")
+}
+
+// printHalsteadMeasures prints the derived Halstead measures selected in the configuration, in its order
+func printHalsteadMeasures(h analyzer.HalsteadMetrics, cfg *Config) {
+	for _, key := range cfg.HalsteadMeasures {
+		measure, _ := findHalsteadMeasure(key)
+		fmt.Fprintln(stdout, Bold+"Calculated "+measure.title+":"+ColorReset, fmt.Sprintf("%.2f", measure.value(h)))
+	}
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// fileImportProfile returns the import profile of a file, within the module that contains it
+func fileImportProfile(path string, src []byte) analyzer.ImportProfile {
+	return analyzer.ClassifyImports(analyzer.ImportPaths(src), enclosingModulePath(filepath.Dir(path)))
+}
+
+// printImportProfile prints the imports of a file by origin, coloring the third-party imports
+func printImportProfile(profile analyzer.ImportProfile, cfg *Config) {
+	if profile.Total() == 0 {
+		return
+	}
+	fmt.Fprintf(stdout, "Imports: %d (stdlib %d, module %d, third-party %s%d%s)\n", profile.Total(), profile.Stdlib, profile.Module,
+		GetColorForThirdPartyImports(profile.ThirdParty, cfg), profile.ThirdParty, ColorReset)
+}
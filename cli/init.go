@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// configKeyComments describes the top-level keys of the configuration in the file written by
+// `zeds init`, other than the function metrics, which are described by their explanation
+var configKeyComments = map[string]string{
+	"distance":                 "Distance of a package from the main sequence, in zeds packages",
+	"docCoverage":              "Minimum percentage of documented exported identifiers of a package",
+	"lcom":                     "Lack of cohesion of the methods of a struct type",
+	"wmc":                      "Weighted methods per type, the summed cyclomatic complexity of its methods",
+	"structFields":             "Number of fields of a struct type",
+	"interfaceMethods":         "Number of methods of an interface type",
+	"thirdPartyImports":        "Third-party imports of a file or package; a high value of 0 disables coloring",
+	"technicalDebt":            "Minutes to fix each unit a metric is beyond its medium threshold, and minutes to write a line",
+	"commentDensityMultiplier": "Weight of the comment density in the maintainability index",
+	"debtMarkers":              "Comment markers counted as technical debt",
+	"halsteadProfile":          "Operator classification of the Halstead metrics: classic, go-keywords or extended",
+	"halsteadMeasures":         "Derived Halstead measures shown next to the volume",
+	"miVariant":                "Maintainability index formula: zeds, original or visualstudio",
+	"functionLength":           "Length metric behind the severity, score, violations and god function flag: loc or lloc",
+	"tabWidth":                 "Display width of a tab character, used by the indentation metric and the longLines lint check",
+	"scoreGrades":              "Grades of the quality score, each covering [min, max)",
+	"uniformMetrics":           "When verbose output warns about files whose function metrics barely vary",
+	"godFunction":              "Flags functions that are complex, long and hard to maintain at once",
+	"qualityGate":              "Conditions checked after a report when enabled; a condition set to 0 is not checked",
+	"theme":                    "Color theme: dark, light or mono, with colors overriding single roles",
+	"lint":                     "Advisory lint checks reported with --lint",
+	"profiles":                 "Named sets of values selected with --profile, e.g. strict: {cyclomatic: {high: 8}}; default applies without --profile",
+	"overrides":                "Values for the files matching a path, e.g. {path: internal/legacy/**, cyclomatic: {high: 25}}",
+}
+
+// configKeyNotes adds how the settings of a function metric work to its comment in the file
+// written by `zeds init`
+var configKeyNotes = map[string]string{
+	"cyclomatic":           "values at or above medium are yellow, at or above high red",
+	"maintainabilityIndex": "below medium is yellow, below low red",
+	"magicNumbers":         "numbers in the allowlist never count",
+	"fanOut":               "only computed by package analysis (-p)",
+	"goroutines":           "a high value of 0 disables coloring",
+	"defers":               "a high value of 0 disables coloring",
+}
+
+// configKeyComment returns the comment above a top-level key in the file written by `zeds init`.
+// A function metric is described by the summary `zeds explain` prints, so that both agree.
+func configKeyComment(key string) (string, bool) {
+	if explanation, ok := findMetricExplanation(key); ok {
+		comment := strings.TrimSuffix(explanation.summary, ".")
+		if note, ok := configKeyNotes[key]; ok {
+			comment += "; " + note
+		}
+		return comment, true
+	}
+	comment, ok := configKeyComments[key]
+	return comment, ok
+}
+
+// initFileNames maps the formats of `zeds init` to the name of the file it creates by default
+var initFileNames = map[string]string{
+	configYAML: "zeds.yaml",
+	configTOML: "zeds.toml",
+	configJSON: "zeds.json",
+}
+
+// handleInitCommand writes a configuration file with the default values, commented in YAML and TOML
+func handleInitCommand(args []string) {
+	format := configYAML
+	var force bool
+	flags := newFlagSet("init", "[<file>]")
+	flags.stringVar(&format, "format", "", "write the file in the `format`: yaml, toml, json")
+	flags.boolVar(&force, "force", "", "overwrite an existing file")
+	positional := parseCommandArgs(flags, args[1:])
+	if _, ok := initFileNames[format]; !ok {
+		failUsage(flags, fmt.Errorf("unknown format '%s'", format))
+	}
+	if len(positional) > 1 {
+		failUsage(flags, fmt.Errorf("expected at most one file"))
+	}
+	path := initFileNames[format]
+	if len(positional) == 1 {
+		path = positional[0]
+		format = configFormat(path)
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+path+" already exists; use --force to overwrite it"+ColorReset)
+		exit(exitError)
+	}
+
+	data, err := json.MarshalIndent(DefaultConfig(), "", "  ")
+	if err == nil {
+		data, err = configFromJSON(data, format)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error encoding config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if format != configJSON {
+		data = commentConfig(data)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error writing config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	fmt.Fprintln(stdout, ColorGreen+"Created "+path+" with the default configuration."+ColorReset)
+
+	// Another configuration file, e.g. one found first by discovery, would take precedence over the new one.
+	locateConfig(".")
+	inUse, errInUse := filepath.Abs(configPath)
+	created, errCreated := filepath.Abs(path)
+	if errInUse == nil && errCreated == nil && inUse != created {
+		if _, err := os.Stat(configPath); err == nil {
+			shown := configPath
+			if rel, err := relToWorkingDir(inUse); err == nil {
+				shown = rel
+			}
+			fmt.Fprintln(stderr, ColorYellow+"Warning: zeds reads "+shown+", not "+path+". Remove "+shown+" or pass --config "+path+"."+ColorReset)
+		}
+	}
+}
+
+// topLevelKey matches the line that starts a top-level key in YAML (key:) or TOML (key =, [key] or [[key]])
+var topLevelKey = regexp.MustCompile(`^(?:\[\[?([A-Za-z]+)\]\]?|([A-Za-z]+)(?::| =))`)
+
+// commentConfig puts the description of each top-level key of a YAML or TOML configuration above
+// its first line
+func commentConfig(data []byte) []byte {
+	var out bytes.Buffer
+	out.WriteString("# zeds configuration. Keys left out keep their default values.\n")
+	commented := map[string]bool{}
+	blank := false
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if m := topLevelKey.FindSubmatch(line); m != nil {
+			key := string(m[1]) + string(m[2])
+			if comment, ok := configKeyComment(key); ok && !commented[key] {
+				commented[key] = true
+				if !blank {
+					out.WriteString("\n")
+				}
+				out.WriteString("# " + comment + "\n")
+			}
+		}
+		out.Write(line)
+		blank = len(bytes.TrimSpace(line)) == 0
+	}
+	return out.Bytes()
+}
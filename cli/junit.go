@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	File       string          `xml:"file,attr"`
+	Line       int             `xml:"line,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// buildJUnit represents each file as a test suite and each function as a test case. A test case
+// fails when a metric reaches its high threshold; medium violations are listed in its output.
+func buildJUnit(files []fileResults, cfg *Config) junitTestSuites {
+	report := junitTestSuites{Name: "zeds"}
+	for _, f := range files {
+		uri := reportURI(f.file)
+		suite := junitTestSuite{Name: uri}
+		for _, res := range f.results {
+			testCase := junitTestCase{Name: res.QualifiedName(), ClassName: uri, File: uri, Line: res.Line,
+				Properties: halsteadProperties(res.Halstead)}
+			high, medium := functionViolations(f.file, res, cfg)
+			if len(high) > 0 {
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d metric(s) reach a high threshold", len(high)),
+					Type:    "threshold",
+					Text:    strings.Join(high, "\n"),
+				}
+				suite.Failures++
+			}
+			if len(medium) > 0 {
+				testCase.SystemOut = strings.Join(medium, "\n")
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suite.Tests = len(suite.TestCases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+	return report
+}
+
+// halsteadProperties lists the Halstead metrics of a function as JUnit test case properties
+func halsteadProperties(h analyzer.HalsteadMetrics) []junitProperty {
+	return []junitProperty{
+		{Name: "halstead.vocabulary", Value: strconv.Itoa(h.Vocabulary)},
+		{Name: "halstead.length", Value: strconv.Itoa(h.Length)},
+		{Name: "halstead.volume", Value: fmt.Sprintf("%.2f", h.Volume)},
+		{Name: "halstead.difficulty", Value: fmt.Sprintf("%.2f", h.Difficulty)},
+		{Name: "halstead.effort", Value: fmt.Sprintf("%.2f", h.Effort)},
+		{Name: "halstead.time", Value: fmt.Sprintf("%.1f", h.Time)},
+		{Name: "halstead.bugs", Value: fmt.Sprintf("%.3f", h.Bugs)},
+	}
+}
+
+// functionViolations returns the messages of a function's high and medium threshold violations
+func functionViolations(file string, res analyzer.MethodResult, cfg *Config) (high, medium []string) {
+	for _, v := range findViolations(file, []analyzer.MethodResult{res}, cfg) {
+		if v.severity == SeverityRed {
+			high = append(high, v.message())
+		} else {
+			medium = append(medium, v.message())
+		}
+	}
+	return high, medium
+}
+
+// writeXML writes a value to stdout as indented XML with an XML declaration
+func writeXML(v interface{}) error {
+	if _, err := io.WriteString(stdout, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(stdout)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(stdout, "\n")
+	return err
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// LintFinding is an advisory issue found in a function, or in the whole file when Function is empty.
+// Column is set by findings about a position within a line, counted in characters.
+type LintFinding struct {
+	Function   string `json:"function,omitempty"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// LintResults runs the lint checks enabled in the configuration over the analyzed functions.
+func LintResults(results []analyzer.MethodResult, cfg *Config) []LintFinding {
+	var findings []LintFinding
+	for _, res := range results {
+		if cfg.Lint.ErrorAndPanic && res.ReturnsError {
+			for _, line := range res.PanicLines {
+				findings = append(findings, LintFinding{
+					Function:   res.QualifiedName(),
+					Line:       line,
+					Message:    "returns an error but also calls panic",
+					Suggestion: "return the error instead, unless the panic guards a truly unrecoverable invariant",
+				})
+			}
+		}
+		if cfg.Lint.IgnoredErrors && res.ErrorHandling.Ignored {
+			findings = append(findings, LintFinding{
+				Function:   res.QualifiedName(),
+				Line:       res.Line,
+				Message:    "assigns errors but never checks or uses them",
+				Suggestion: "check each error, return it, or assign it to _ to ignore it explicitly",
+			})
+		}
+		if cfg.Lint.ComplexInit && res.MethodName == "init" && res.Receiver == "" && res.Cyclomatic >= cfg.Lint.InitComplexity {
+			findings = append(findings, LintFinding{
+				Function:   res.QualifiedName(),
+				Line:       res.Line,
+				Message:    fmt.Sprintf("has cyclomatic complexity %d (limit %d)", res.Cyclomatic, cfg.Lint.InitComplexity),
+				Suggestion: "move the logic into a function that returns an error and call it explicitly, or initialize lazily with sync.Once; init runs whenever the package is imported and cannot report failures",
+			})
+		}
+	}
+	return findings
+}
+
+// LintFile runs the file-level lint checks enabled in the configuration over Go source.
+func LintFile(src []byte, cfg *Config) []LintFinding {
+	var findings []LintFinding
+	if cfg.Lint.LongFile {
+		if lines := strings.Count(string(src), "\n") + 1; lines >= cfg.Lint.MaxFileLines {
+			findings = append(findings, LintFinding{
+				Line:       1,
+				Message:    fmt.Sprintf("has %d lines (limit %d)", lines, cfg.Lint.MaxFileLines),
+				Suggestion: splitFileSuggestion,
+			})
+		}
+		if functions := analyzer.CountFunctions(src); functions >= cfg.Lint.MaxFileFunctions {
+			findings = append(findings, LintFinding{
+				Line:       1,
+				Message:    fmt.Sprintf("declares %d functions (limit %d)", functions, cfg.Lint.MaxFileFunctions),
+				Suggestion: splitFileSuggestion,
+			})
+		}
+	}
+	if cfg.Lint.LongLines {
+		for _, long := range analyzer.FindLongLines(src, cfg.Lint.MaxLineWidth, cfg.AnalyzerOptions()) {
+			findings = append(findings, LintFinding{
+				Line:       long.Line,
+				Column:     long.Column,
+				Message:    fmt.Sprintf("line is %d columns wide (limit %d)", long.Width, cfg.Lint.MaxLineWidth),
+				Suggestion: "break the line, e.g. after a comma of a long argument list or before a && or || of a long condition",
+			})
+		}
+	}
+	return findings
+}
+
+// splitFileSuggestion is the suggestion of the long file findings
+const splitFileSuggestion = "split the file by responsibility, e.g. move each type with its methods, or each command, into its own file"
+
+// printLintFindings prints the lint findings section
+func printLintFindings(findings []LintFinding) {
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"Lint:"+ColorReset)
+	if len(findings) == 0 {
+		fmt.Fprintln(stdout, ColorGreen+"No lint findings."+ColorReset)
+		return
+	}
+	for _, f := range findings {
+		switch {
+		case f.Function == "" && f.Column > 0:
+			fmt.Fprintf(stdout, "  - %sfile%s (line %d, column %d): %s%s%s\n", ColorCyan, ColorReset, f.Line, f.Column, ColorYellow, f.Message, ColorReset)
+		case f.Function == "":
+			fmt.Fprintf(stdout, "  - %sfile%s: %s%s%s\n", ColorCyan, ColorReset, ColorYellow, f.Message, ColorReset)
+		default:
+			fmt.Fprintf(stdout, "  - %s%s%s (line %d): %s%s%s\n", ColorCyan, f.Function, ColorReset, f.Line, ColorYellow, f.Message, ColorReset)
+		}
+		fmt.Fprintln(stdout, "      Suggestion: "+f.Suggestion)
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// ListedDeclaration is an entry of `list --format json` output: a declaration and its file
+type ListedDeclaration struct {
+	File string `json:"file"`
+	analyzer.Declaration
+}
+
+// handleListCommand lists the functions, methods and types declared in Go files with their
+// locations, as an inventory of the code
+func handleListCommand(args []string) {
+	var patterns, excludes []string
+	var dir string
+	var recursive bool
+	format := "text"
+	flags := newFlagSet("list", "-f <file>...", "-d <dir> [-r]")
+	flags.listVar(&patterns, "file", "f", "", "list the Go `file`s or globs (** matches any directories); more may follow as arguments")
+	flags.stringVar(&dir, "dir", "d", "list the Go files in the `directory`; a trailing /... also lists its subdirectories")
+	flags.boolVar(&recursive, "recursive", "r", "also list the subdirectories of --dir")
+	flags.stringVar(&format, "format", "", "write the list in the `format`: text, json")
+	flags.listVar(&excludes, "exclude", "", "", "skip the files matching the `pattern`")
+	patterns = append(patterns, parseCommandArgs(flags, args[1:])...)
+	// Only the exclude patterns are shared with analyze; ZEDS_FORMAT, for one, names a report format.
+	if err := flags.applyEnvironment("exclude"); err != nil {
+		failUsage(flags, err)
+	}
+	dir, recursive = recursiveDir(dir, recursive)
+	switch {
+	case len(patterns) == 0 && dir == "":
+		failUsage(flags, fmt.Errorf("no file or directory specified"))
+	case len(patterns) > 0 && dir != "":
+		failUsage(flags, fmt.Errorf("--file and --dir cannot be combined"))
+	case recursive && dir == "":
+		failUsage(flags, fmt.Errorf("--recursive requires --dir"))
+	case format != "text" && format != "json":
+		failUsage(flags, fmt.Errorf("unknown format '%s'", format))
+	}
+
+	files, err := listedFiles(dir, recursive, patterns, excludes)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error listing files: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+
+	listed := make([]ListedDeclaration, 0)
+	for _, file := range files {
+		declarations, err := analyzer.AnalyzeFile(file)
+		if err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error parsing file: "+err.Error()+ColorReset)
+			exit(exitError)
+		}
+		for _, d := range declarations {
+			listed = append(listed, ListedDeclaration{File: file, Declaration: d})
+		}
+	}
+
+	if format == "json" {
+		if err := writeJSON(listed); err != nil {
+			fmt.Fprintln(stderr, "Error writing list: "+err.Error())
+			exit(exitError)
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Kind\tName\tLocation")
+	for _, d := range listed {
+		kind := d.Kind
+		if d.Kind == analyzer.DeclType {
+			kind = d.TypeKind
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s:%d\n", kind, d.QualifiedName(), d.File, d.Line)
+	}
+	tw.Flush()
+}
+
+// listedFiles collects the files to list the way analyze does: the files of a directory or the
+// files matching patterns, where several files skip those matching the exclude patterns of the
+// command line and of the .zedsignore file at the root of the analysis
+func listedFiles(dir string, recursive bool, patterns, excludes []string) ([]string, error) {
+	root := "."
+	var files []string
+	var err error
+	if dir != "" {
+		root = dir
+		files, err = collectGoFiles(dir, recursive)
+	} else {
+		files, err = expandFilePatterns(patterns)
+	}
+	if err != nil || (dir == "" && len(files) == 1) {
+		return files, err
+	}
+	if excludes, err = readExcludes(root, excludes); err != nil {
+		return nil, err
+	}
+	kept := files[:0]
+	for _, file := range files {
+		if !excludedFile(root, file, excludes) {
+			kept = append(kept, file)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// markdownTopOffenders is the number of functions listed in the markdown report without --markdown-all
+const markdownTopOffenders = 10
+
+// severityBadge returns the markdown status text of a severity, since markdown cannot render ANSI colors
+func severityBadge(s Severity) string {
+	switch s {
+	case SeverityRed:
+		return "🔴 High"
+	case SeverityYellow:
+		return "🟡 Medium"
+	}
+	return "🟢 OK"
+}
+
+// escapeMarkdown escapes characters that would break a markdown table cell
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// markdownRow is a function listed in the markdown report together with its file
+type markdownRow struct {
+	file     string
+	res      analyzer.MethodResult
+	severity Severity
+}
+
+// buildMarkdown renders a compact GitHub-flavored markdown report for pull request comments and
+// job summaries: aggregate metrics followed by the worst offenders, or by every function when all is set
+func buildMarkdown(files []fileResults, cfg *Config, all bool) string {
+	var b strings.Builder
+	var results []analyzer.MethodResult
+	var rows []markdownRow
+	counts := map[Severity]int{}
+	for _, f := range files {
+		results = append(results, f.results...)
+		for _, res := range f.results {
+			severity := GetSeverity(res, cfg.ForFile(f.file))
+			counts[severity]++
+			if all || severity != SeverityGreen {
+				rows = append(rows, markdownRow{file: f.file, res: res, severity: severity})
+			}
+		}
+	}
+
+	if len(files) == 1 {
+		fmt.Fprintf(&b, "### Zeds report: `%s`\n\n", filepath.Base(files[0].file))
+	} else {
+		fmt.Fprintf(&b, "### Zeds report: %d files\n\n", len(files))
+	}
+	summary := analyzer.Summarize(results)
+	score := CalculateScore(results, cfg)
+	b.WriteString("| Functions | 🔴 High | 🟡 Medium | Total CC | Average MI | Score | Grade |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: | ---: | :---: |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %.2f | %.1f | %s |\n\n", summary.Functions, counts[SeverityRed],
+		counts[SeverityYellow], summary.TotalCyclomatic, summary.AverageMI, score, GetGradeForScore(score, cfg))
+
+	if len(rows) == 0 {
+		fmt.Fprintf(&b, "✅ No threshold violations in %d function(s).\n", len(results))
+		return b.String()
+	}
+
+	// Worst first: by severity, then by how far the worst metric is past its high threshold.
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].severity != rows[j].severity {
+			return rows[i].severity > rows[j].severity
+		}
+		return dominantRatio(rows[i].res, cfg.ForFile(rows[i].file)) > dominantRatio(rows[j].res, cfg.ForFile(rows[j].file))
+	})
+	if !all {
+		if len(rows) > markdownTopOffenders {
+			fmt.Fprintf(&b, "Worst %d of %d function(s) violating thresholds:\n\n", markdownTopOffenders, len(rows))
+			rows = rows[:markdownTopOffenders]
+		} else {
+			fmt.Fprintf(&b, "%d of %d function(s) violate thresholds:\n\n", len(rows), len(results))
+		}
+	}
+
+	multiFile := len(files) > 1
+	if multiFile {
+		b.WriteString("| Function | File | CC | LOC | MI | Effort | Bugs | Status |\n")
+		b.WriteString("| --- | --- | ---: | ---: | ---: | ---: | ---: | --- |\n")
+	} else {
+		b.WriteString("| Function | CC | LOC | MI | Effort | Bugs | Status |\n")
+		b.WriteString("| --- | ---: | ---: | ---: | ---: | ---: | --- |\n")
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&b, "| `%s` |", escapeMarkdown(row.res.QualifiedName()))
+		if multiFile {
+			fmt.Fprintf(&b, " `%s:%d` |", escapeMarkdown(reportURI(row.file)), row.res.Line)
+		}
+		fmt.Fprintf(&b, " %d | %d | %.2f | %.0f | %.3f | %s |\n", row.res.Cyclomatic, row.res.LOC,
+			row.res.MaintainabilityIndex, row.res.Halstead.Effort, row.res.Halstead.Bugs, severityBadge(row.severity))
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goModule is a Go module found under an analyzed directory
+type goModule struct {
+	path string
+	dir  string
+}
+
+// discoverModules returns the modules under dir. When dir holds a go.work file, the modules are
+// the ones it uses; otherwise every directory containing a go.mod file is a module.
+func discoverModules(dir string) ([]goModule, error) {
+	workPath := filepath.Join(dir, "go.work")
+	if data, err := os.ReadFile(workPath); err == nil {
+		work, err := modfile.ParseWork(workPath, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		var modules []goModule
+		for _, use := range work.Use {
+			module, err := readModule(filepath.Join(dir, filepath.FromSlash(use.Path)))
+			if err != nil {
+				return nil, err
+			}
+			modules = append(modules, module)
+		}
+		return modules, nil
+	}
+
+	var modules []goModule
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || entry.Name() != "go.mod" {
+			return err
+		}
+		module, err := readModule(filepath.Dir(path))
+		if err == nil {
+			modules = append(modules, module)
+		}
+		return err
+	})
+	return modules, err
+}
+
+// readModule reads the module path from the go.mod file in dir
+func readModule(dir string) (goModule, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return goModule{}, err
+	}
+	return goModule{path: modfile.ModulePath(data), dir: filepath.Clean(dir)}, nil
+}
+
+// groupByModule assigns each file to the innermost module containing it. Files outside
+// every module are collected in a trailing group without a name.
+func groupByModule(files []string, modules []goModule) []fileGroup {
+	// Deeper modules first, so that nested modules win over their parents.
+	sort.SliceStable(modules, func(i, j int) bool { return len(modules[i].dir) > len(modules[j].dir) })
+	byModule := map[string][]string{}
+	var outside []string
+	for _, file := range files {
+		found := false
+		for _, module := range modules {
+			if rel, err := filepath.Rel(module.dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+				byModule[module.dir] = append(byModule[module.dir], file)
+				found = true
+				break
+			}
+		}
+		if !found {
+			outside = append(outside, file)
+		}
+	}
+
+	var groups []fileGroup
+	for _, module := range modules {
+		if files := byModule[module.dir]; files != nil {
+			groups = append(groups, fileGroup{kind: "Module", name: module.path, files: files})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].name < groups[j].name })
+	if outside != nil {
+		groups = append(groups, fileGroup{kind: "Outside of any module", files: outside})
+	}
+	return groups
+}
+
+// enclosingModulePath returns the path of the module containing dir, read from the nearest go.mod
+// file in dir or above it, or an empty string outside any module
+func enclosingModulePath(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if module, err := readModule(dir); err == nil {
+			return module.path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Color modes accepted by the global --color flag
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// colorModes lists the values accepted by --color
+var colorModes = []string{colorAuto, colorAlways, colorNever}
+
+// colorMode is the color mode selected with --color, or with --no-color for never
+var colorMode = colorAuto
+
+// stdout and stderr are where commands print. All output goes through them so that the color
+// mode applies everywhere, and so that --output can send reports to a file. Errors go to stderr,
+// which --output leaves on the terminal.
+var (
+	stdout = &colorWriter{w: os.Stdout}
+	stderr = &colorWriter{w: os.Stderr}
+)
+
+// ansiSequence matches the ANSI escape sequences used for colors and styles
+var ansiSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// colorWriter writes to an underlying writer, removing ANSI escape sequences when colors are off
+// and applying the color theme when they are on
+type colorWriter struct {
+	w     io.Writer
+	color bool
+}
+
+// Write writes p, replacing the color constants with the colors of the theme when colors are on
+func (c *colorWriter) Write(p []byte) (int, error) {
+	if c.color && themeSequences == nil {
+		return c.w.Write(p)
+	}
+	out := ansiSequence.ReplaceAllFunc(p, func(sequence []byte) []byte {
+		if !c.color {
+			return nil
+		}
+		if themed, ok := themeSequences[string(sequence)]; ok {
+			return []byte(themed)
+		}
+		return sequence
+	})
+	if _, err := c.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// setOutput makes the writer write to f, with colors if the color mode allows them for f
+func (c *colorWriter) setOutput(f *os.File) {
+	c.w = f
+	c.color = useColor(f)
+}
+
+// useColor reports whether output to f is colored. In auto mode, colors are used only on a
+// terminal that renders them and when the NO_COLOR environment variable is not set to a non-empty
+// value.
+func useColor(f *os.File) bool {
+	switch colorMode {
+	case colorAlways:
+		enableVirtualTerminal(f)
+		return true
+	case colorNever:
+		return false
+	}
+	return os.Getenv("NO_COLOR") == "" && isTerminal(f) && enableVirtualTerminal(f)
+}
+
+// extractColorFlags removes the global --color and --no-color flags from the arguments and sets
+// the color mode of standard output and standard error
+func extractColorFlags(args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		var mode string
+		var err error
+		switch {
+		case args[i] == "--no-color":
+			mode = colorNever
+		case args[i] == "--color":
+			mode, err = nextArg(args, &i)
+		case strings.HasPrefix(args[i], "--color="):
+			mode = strings.TrimPrefix(args[i], "--color=")
+		default:
+			rest = append(rest, args[i])
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(colorModes, mode) {
+			return nil, fmt.Errorf("unknown color mode '%s'. Valid modes: %s", mode, strings.Join(colorModes, ", "))
+		}
+		colorMode = mode
+	}
+	stdout.setOutput(os.Stdout)
+	stderr.setOutput(os.Stderr)
+	return rest, nil
+}
+
+// logVerbose writes a diagnostic about the run to standard error when --verbose is set
+func logVerbose(opts analyzeOptions, format string, args ...interface{}) {
+	if opts.verbose {
+		fmt.Fprintln(stderr, Italic+ColorYellow+fmt.Sprintf(format, args...)+ItalicReset+ColorReset)
+	}
+}
+
+// logElapsed writes the number of analyzed files and the time taken to standard error when --verbose is set
+func logElapsed(opts analyzeOptions, files int) {
+	elapsed := time.Since(opts.started)
+	logVerbose(opts, "Analyzed %d file(s) in %s (%.1f files/s).", files, elapsed.Round(time.Millisecond), float64(files)/elapsed.Seconds())
+}
+
+// redirectOutput sends everything a command prints to standard output to the file at path,
+// creating its parent directories, so that reports need no shell redirection. Errors are printed
+// to standard error and stay out of the file. The file is closed when the command exits.
+func redirectOutput(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	stdout.setOutput(f)
+	exitHooks = append(exitHooks, func() {
+		stdout.setOutput(os.Stdout)
+		if err := f.Close(); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error writing output: "+err.Error()+ColorReset)
+		}
+	})
+	return nil
+}
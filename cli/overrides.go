@@ -0,0 +1,67 @@
+package cli
+
+import "fmt"
+
+// thresholdFlag is an analyze option that overrides a threshold of the configuration for one run
+type thresholdFlag struct {
+	name string
+	// metric is the key of the threshold metric whose threshold the option sets
+	metric string
+	// level is the threshold the option sets, as named in the configuration: medium, high or low
+	level string
+	field func(cfg *Config) *float64
+}
+
+// thresholdFlags lists the threshold override options of the analyze command
+var thresholdFlags = []thresholdFlag{
+	{"cc-medium", "cyclomatic", "medium", func(c *Config) *float64 { return &c.Cyclomatic.Medium }},
+	{"cc-high", "cyclomatic", "high", func(c *Config) *float64 { return &c.Cyclomatic.High }},
+	{"npath-medium", "npath", "medium", func(c *Config) *float64 { return &c.NPath.Medium }},
+	{"npath-high", "npath", "high", func(c *Config) *float64 { return &c.NPath.High }},
+	{"abc-medium", "abc", "medium", func(c *Config) *float64 { return &c.ABC.Medium }},
+	{"abc-high", "abc", "high", func(c *Config) *float64 { return &c.ABC.High }},
+	{"nesting-medium", "nesting", "medium", func(c *Config) *float64 { return &c.Nesting.Medium }},
+	{"nesting-high", "nesting", "high", func(c *Config) *float64 { return &c.Nesting.High }},
+	{"mi-medium", "maintainabilityIndex", "medium", func(c *Config) *float64 { return &c.MaintainabilityIndex.Medium }},
+	{"mi-low", "maintainabilityIndex", "low", func(c *Config) *float64 { return &c.MaintainabilityIndex.Low }},
+	{"loc-medium", "loc", "medium", func(c *Config) *float64 { return &c.LOC.Medium }},
+	{"loc-high", "loc", "high", func(c *Config) *float64 { return &c.LOC.High }},
+	{"lloc-medium", "lloc", "medium", func(c *Config) *float64 { return &c.LLOC.Medium }},
+	{"lloc-high", "lloc", "high", func(c *Config) *float64 { return &c.LLOC.High }},
+	{"params-medium", "params", "medium", func(c *Config) *float64 { return &c.Params.Medium }},
+	{"params-high", "params", "high", func(c *Config) *float64 { return &c.Params.High }},
+}
+
+// defineThresholdFlags defines the threshold override options, whose values are stored in
+// opts.thresholds in the order of thresholdFlags
+func defineThresholdFlags(flags *flagSet, opts *analyzeOptions) {
+	opts.thresholds = make([]*float64, len(thresholdFlags))
+	for i, tf := range thresholdFlags {
+		flags.optionalFloatVar(&opts.thresholds[i], tf.name, "", "override the configured "+tf.metric+"."+tf.level+" threshold with `value`")
+	}
+}
+
+// applyThresholdFlags overrides the thresholds of the configuration with the values of the
+// threshold options. It fails when a metric's medium threshold ends up beyond its high one.
+func applyThresholdFlags(cfg *Config, opts analyzeOptions) error {
+	overridden := make(map[string]bool)
+	for i, tf := range thresholdFlags {
+		if value := opts.thresholds[i]; value != nil {
+			*tf.field(cfg) = *value
+			overridden[tf.metric] = true
+		}
+	}
+	for _, metric := range thresholdMetrics {
+		if !overridden[metric.key] {
+			continue
+		}
+		medium, high := metric.limits(cfg)
+		if metric.lowerIsWorse && medium < high {
+			return fmt.Errorf("the medium threshold of %s (%v) is below its low threshold (%v)", metric.key, medium, high)
+		}
+		if !metric.lowerIsWorse && medium > high {
+			return fmt.Errorf("the medium threshold of %s (%v) is above its high threshold (%v)", metric.key, medium, high)
+		}
+	}
+	return nil
+}
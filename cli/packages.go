@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+	"golang.org/x/tools/go/packages"
+)
+
+// callCounts holds the call graph metrics of a function
+type callCounts struct {
+	fanIn, fanOut int
+}
+
+// loadPackageGroups resolves Go package patterns such as ./... into one file group per package.
+// Only the files included in the build for the given tags are listed; test files are excluded.
+// The packages and their dependencies are type-checked from source to compute the fan-in and
+// fan-out of their functions, which does not depend on export data matching the installed Go.
+// Type errors only leave the affected calls unresolved and are returned as warnings, while errors
+// listing or parsing a package are fatal.
+func loadPackageGroups(patterns []string, tags []string) ([]fileGroup, []string, error) {
+	config := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+		packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo}
+	if tags != nil {
+		config.BuildFlags = []string{"-tags=" + strings.Join(tags, ",")}
+	}
+	pkgs, err := packages.Load(config, patterns...)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings []string
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			if pkgErr.Kind != packages.TypeError {
+				return nil, nil, fmt.Errorf("%s: %v", pkg.PkgPath, pkgErr)
+			}
+			warnings = append(warnings, pkgErr.Error())
+		}
+	}
+	fanIn := countCallSites(pkgs)
+	var groups []fileGroup
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) > 0 {
+			groups = append(groups, fileGroup{kind: "Package", name: pkg.PkgPath, files: pkg.GoFiles, calls: packageCalls(pkg, fanIn)})
+		}
+	}
+	return groups, warnings, nil
+}
+
+// countCallSites builds the call graph of the packages, returning the number of call sites of
+// each function. Only calls within the packages count, so the fan-in of a function is relative
+// to the analyzed patterns, e.g. module-wide for ./...
+func countCallSites(pkgs []*packages.Package) map[*types.Func]int {
+	sites := make(map[*types.Func]int)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					if fn := analyzer.Callee(call, pkg.TypesInfo); fn != nil {
+						sites[fn]++
+					}
+				}
+				return true
+			})
+		}
+	}
+	return sites
+}
+
+// packageCalls computes the call graph metrics of the functions of a type-checked package, by file and line
+func packageCalls(pkg *packages.Package, fanIn map[*types.Func]int) map[string]map[int]callCounts {
+	calls := make(map[string]map[int]callCounts)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			pos := pkg.Fset.Position(fn.Pos())
+			if calls[pos.Filename] == nil {
+				calls[pos.Filename] = make(map[int]callCounts)
+			}
+			counts := callCounts{fanOut: analyzer.CalculateFanOut(fn.Body, pkg.TypesInfo)}
+			if obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+				counts.fanIn = fanIn[obj]
+			}
+			calls[pos.Filename][pos.Line] = counts
+		}
+	}
+	return calls
+}
+
+// analyzePackages analyzes the packages matching the patterns, grouping the results by package
+func analyzePackages(patterns []string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	groups, warnings, err := loadPackageGroups(patterns, opts.buildTags)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading packages: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	for _, warning := range warnings {
+		logVerbose(opts, "Type error, calls left unresolved: %s", warning)
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		root = "."
+	}
+	analyzeGroups(groups, root, cfg, opts, budgets)
+}
+
+// riskiestFunctions is the number of functions listed in the package mode risk summary
+const riskiestFunctions = 5
+
+// printRiskiestFunctions lists the functions that reach the medium cyclomatic complexity threshold
+// and are called most often, as these are the riskiest to change
+func printRiskiestFunctions(files []fileResults, cfg *Config) {
+	type candidate struct {
+		file string
+		res  analyzer.MethodResult
+	}
+	var candidates []candidate
+	for _, f := range files {
+		for _, res := range f.results {
+			if res.FanIn > 0 && GetColorForCyclomatic(res.Cyclomatic, cfg) != ColorGreen {
+				candidates = append(candidates, candidate{f.file, res})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].res.FanIn != candidates[j].res.FanIn {
+			return candidates[i].res.FanIn > candidates[j].res.FanIn
+		}
+		return candidates[i].res.Cyclomatic > candidates[j].res.Cyclomatic
+	})
+	if len(candidates) > riskiestFunctions {
+		candidates = candidates[:riskiestFunctions]
+	}
+	fmt.Fprintln(stdout, ColorCyan+"Riskiest Functions (complex and widely called):"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	for _, c := range candidates {
+		fmt.Fprintf(stdout, "  - %s%s%s (%s:%d): CC %s%d%s, fan-in %d\n", ColorCyan, c.res.QualifiedName(), ColorReset,
+			reportURI(c.file), c.res.Line, GetColorForCyclomatic(c.res.Cyclomatic, cfg), c.res.Cyclomatic, ColorReset, c.res.FanIn)
+	}
+	fmt.Fprintln(stdout)
+}
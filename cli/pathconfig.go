@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PathOverride holds configuration values that apply to the files matching a path pattern instead
+// of the values of the project, e.g. relaxed thresholds for a legacy package. In the configuration
+// file, the values are written next to the path: {"path": "internal/legacy/**", "cyclomatic": {"high": 25}}.
+type PathOverride struct {
+	Path   string
+	Values json.RawMessage
+}
+
+// UnmarshalJSON reads the path of an override and keeps its other keys as its values
+func (o *PathOverride) UnmarshalJSON(data []byte) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("overrides: each override must be an object with a path")
+	}
+	if err := json.Unmarshal(values["path"], &o.Path); err != nil || o.Path == "" {
+		return fmt.Errorf("overrides: each override needs a path")
+	}
+	delete(values, "path")
+	if _, ok := values["overrides"]; ok {
+		return fmt.Errorf("overrides: the override of %s cannot have overrides", o.Path)
+	}
+	var err error
+	o.Values, err = json.Marshal(values)
+	return err
+}
+
+// MarshalJSON writes the values of an override next to its path
+func (o PathOverride) MarshalJSON() ([]byte, error) {
+	values := map[string]json.RawMessage{}
+	if len(o.Values) > 0 {
+		if err := json.Unmarshal(o.Values, &values); err != nil {
+			return nil, err
+		}
+	}
+	path, err := json.Marshal(o.Path)
+	if err != nil {
+		return nil, err
+	}
+	values["path"] = path
+	return json.Marshal(values)
+}
+
+// matches reports whether the override applies to a slash-separated path relative to the
+// directory of the configuration file. The pattern is matched like an exclude pattern, so
+// internal/legacy and internal/legacy/** both match every file under internal/legacy.
+func (o PathOverride) matches(rel string) bool {
+	return matchesPattern(rel, o.Path)
+}
+
+// ForFile returns the configuration for a file: the configuration with the values of every
+// override whose pattern matches the file applied in order, or the configuration itself when none
+// does. Paths are matched relative to the directory of the configuration file.
+func (c *Config) ForFile(path string) *Config {
+	if len(c.Overrides) == 0 || path == "" {
+		return c
+	}
+	rel, ok := configRelativePath(path)
+	if !ok {
+		return c
+	}
+	var matched []string
+	for i, o := range c.Overrides {
+		if o.matches(rel) {
+			matched = append(matched, strconv.Itoa(i))
+		}
+	}
+	if len(matched) == 0 {
+		return c
+	}
+	key := strings.Join(matched, ",")
+	if cfg, ok := c.fileConfigs[key]; ok {
+		return cfg
+	}
+	cfg, err := c.withOverrides(matched)
+	if err != nil {
+		// Validate applies every override, so a valid configuration cannot get here.
+		return c
+	}
+	if c.fileConfigs == nil {
+		c.fileConfigs = make(map[string]*Config)
+	}
+	c.fileConfigs[key] = cfg
+	return cfg
+}
+
+// withOverrides returns a copy of the configuration with the values of the overrides at the given
+// indexes applied in order. The copy has no overrides of its own.
+func (c *Config) withOverrides(indexes []string) (*Config, error) {
+	cfg, err := c.clone()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Overrides = nil
+	for _, index := range indexes {
+		i, _ := strconv.Atoi(index)
+		if err := json.Unmarshal(c.Overrides[i].Values, cfg); err != nil {
+			return nil, fmt.Errorf("overrides: %s: %v", c.Overrides[i].Path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// clone returns a deep copy of the configuration
+func (c *Config) clone() (*Config, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validateOverrides checks that every override yields a valid configuration
+func (c *Config) validateOverrides() error {
+	for i, o := range c.Overrides {
+		cfg, err := c.withOverrides([]string{strconv.Itoa(i)})
+		if err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("overrides: %s: %v", o.Path, err)
+		}
+	}
+	return nil
+}
+
+// configRelativePath returns a path relative to the directory of the configuration file, with
+// forward slashes. It fails for paths outside that directory.
+func configRelativePath(path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	dir, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// exitHooks run before the process terminates, in reverse order of registration.
+var exitHooks []func()
+
+// runExitHooks runs and clears the registered exit hooks
+func runExitHooks() {
+	hooks := exitHooks
+	exitHooks = nil
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+}
+
+// Exit statuses of zeds, so that CI pipelines can tell failed quality gates from failed runs
+const (
+	// exitViolations reports that the analysis succeeded but a budget or --fail-on gate was breached
+	exitViolations = 1
+	// exitError reports that the command could not run, e.g. because of invalid arguments or unreadable files
+	exitError = 2
+)
+
+// exit runs the exit hooks and terminates the process with the given status code.
+// All commands exit through here so that profiles are flushed on every code path.
+func exit(code int) {
+	runExitHooks()
+	os.Exit(code)
+}
+
+// extractProfileFlags removes the global --cpuprofile and --memprofile flags from the arguments
+func extractProfileFlags(args []string) ([]string, string, string, error) {
+	var rest []string
+	var cpuProfile, memProfile string
+	var err error
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--cpuprofile":
+			cpuProfile, err = nextArg(args, &i)
+		case "--memprofile":
+			memProfile, err = nextArg(args, &i)
+		default:
+			rest = append(rest, args[i])
+		}
+		if err != nil {
+			return nil, "", "", err
+		}
+	}
+	return rest, cpuProfile, memProfile, nil
+}
+
+// startProfiling starts CPU profiling and registers exit hooks that stop it and write the heap profile
+func startProfiling(cpuProfile, memProfile string) error {
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		exitHooks = append(exitHooks, func() {
+			pprof.StopCPUProfile()
+			f.Close()
+		})
+	}
+	if memProfile != "" {
+		exitHooks = append(exitHooks, func() {
+			if err := writeHeapProfile(memProfile); err != nil {
+				fmt.Fprintln(stderr, ColorRed+"Error writing memory profile: "+err.Error()+ColorReset)
+			}
+		})
+	}
+	return nil
+}
+
+// writeHeapProfile writes an up-to-date heap profile to the given path
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	runtime.GC()
+	return pprof.WriteHeapProfile(f)
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// progressWidth is the number of characters of the progress bar
+const progressWidth = 30
+
+// progressInterval is the minimum time between two redraws of the progress bar
+const progressInterval = 100 * time.Millisecond
+
+// progress draws a progress bar with the files analyzed per second on stderr, so that long runs
+// do not look hung. A nil progress draws nothing.
+type progress struct {
+	total, done int
+	started     time.Time
+	drawn       time.Time
+	// width is the length of the last line drawn, which is blanked when the bar is cleared
+	width int
+}
+
+// newProgress returns a progress bar for a run over total files, or nil when it would not be
+// seen or would get in the way: when stderr is not a terminal, when the text report is printed
+// to the terminal as the run goes, or when verbose diagnostics are written to stderr.
+func newProgress(total int, opts analyzeOptions) *progress {
+	streaming := opts.format == "text" && !opts.quiet && stdout.w == os.Stdout && isTerminal(os.Stdout)
+	if !isTerminal(os.Stderr) || streaming || opts.verbose {
+		return nil
+	}
+	now := time.Now()
+	return &progress{total: total, started: now, drawn: now}
+}
+
+// advance records that a file was analyzed and redraws the bar if it is due
+func (p *progress) advance() {
+	if p == nil {
+		return
+	}
+	p.done++
+	if time.Since(p.drawn) < progressInterval && p.done < p.total {
+		return
+	}
+	p.drawn = time.Now()
+	filled := progressWidth * p.done / max(p.total, 1)
+	rate := float64(p.done) / time.Since(p.started).Seconds()
+	line := fmt.Sprintf("[%s%s] %d/%d files, %.1f files/s", strings.Repeat("=", filled),
+		strings.Repeat(" ", progressWidth-filled), p.done, p.total, rate)
+	fmt.Fprint(stderr, "\r"+line+strings.Repeat(" ", max(p.width-len(line), 0)))
+	p.width = len(line)
+}
+
+// clear removes the bar from the terminal, before the report or a message is printed
+func (p *progress) clear() {
+	if p == nil || p.width == 0 {
+		return
+	}
+	fmt.Fprint(stderr, "\r"+strings.Repeat(" ", p.width)+"\r")
+	p.width = 0
+}
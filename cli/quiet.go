@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// printQuietReport prints the text report of --quiet: one line per threshold violation, the
+// budget, --fail-on and quality gate outcomes, and a final verdict
+func printQuietReport(files []fileResults, budgetViolations []BudgetViolation, failed bool, gate []gateCondition, cfg *Config, opts analyzeOptions) {
+	count := 0
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			fmt.Fprintf(stdout, "%s:%d: %s%s%s %s\n", reportURI(f.file), v.function.Line,
+				v.severity.Color(), severityLevelName(v.severity), ColorReset, v.message())
+			count++
+		}
+	}
+	if count > 0 {
+		fmt.Fprintln(stdout)
+	}
+	if opts.budgetsPath != "" && len(budgetViolations) > 0 {
+		printBudgetViolations(budgetViolations)
+		fmt.Fprintln(stdout)
+	}
+	if failed {
+		fmt.Fprintln(stdout, ColorRed+"Failed: a function breaches a threshold requested with --fail-on ("+strings.Join(opts.failOn, ", ")+")."+ColorReset)
+	}
+	printQualityGate(stdout, gate)
+
+	summary := fmt.Sprintf("%d threshold violation(s)", count)
+	if len(budgetViolations) > 0 || failed || !gatePassed(gate) {
+		fmt.Fprintln(stdout, Bold+ColorRed+"FAILED"+ColorReset+": "+summary+".")
+	} else {
+		fmt.Fprintln(stdout, Bold+ColorGreen+"PASSED"+ColorReset+": "+summary+".")
+	}
+}
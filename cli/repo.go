@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// splitRepoRef splits "url@ref" into the repository URL and the ref. The ref is optional;
+// an "@" before the last path element (as in git@github.com:org/project) is part of the URL.
+func splitRepoRef(repo string) (string, string) {
+	at := strings.LastIndex(repo, "@")
+	if at <= strings.LastIndexAny(repo, "/:") {
+		return repo, ""
+	}
+	return repo[:at], repo[at+1:]
+}
+
+// cloneRepo makes a shallow clone of the repository at the given ref (or its default branch) into dir.
+// Fetching the ref directly works for branches, tags and, on most hosts, commit hashes.
+func cloneRepo(url, ref, dir string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git is required to analyze a repository: %v", err)
+	}
+	commands := [][]string{{"clone", "--quiet", "--depth", "1", url, dir}}
+	if ref != "" {
+		commands = [][]string{
+			{"init", "--quiet", dir},
+			{"-C", dir, "fetch", "--quiet", "--depth", "1", url, ref},
+			{"-C", dir, "checkout", "--quiet", "FETCH_HEAD"},
+		}
+	}
+	for _, args := range commands {
+		cmd := exec.Command("git", args...)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	return nil
+}
+
+// analyzeRepo clones a remote repository into a temporary directory, analyzes it recursively
+// and removes the clone again, also when the run exits with a failure status
+func analyzeRepo(repo string, cfg *Config, opts analyzeOptions, budgets Budgets) {
+	url, ref := splitRepoRef(repo)
+	dir, err := os.MkdirTemp("", "zeds-repo-")
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error creating temporary directory: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	exitHooks = append(exitHooks, func() {
+		_ = os.Chdir(wd)
+		_ = os.RemoveAll(dir)
+	})
+
+	fmt.Fprintln(stderr, Italic+"Cloning "+repo+"..."+ItalicReset)
+	if err := cloneRepo(url, ref, dir); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error cloning repository: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+
+	// Analyze from inside the clone so that files are reported relative to the repository root.
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	opts.recursive = true
+	analyzeDirectory(".", cfg, opts, budgets)
+}
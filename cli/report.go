@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// ReportSchemaVersion is the version of the JSON report format. Bump it whenever a field of the
+// report is added, removed or changes meaning: the schema of `zeds schema` rejects unknown
+// properties, so a new field breaks the validation of reports against the previous version.
+const ReportSchemaVersion = 3
+
+// Report is the envelope of the JSON report written by `analyze --format json`. Score is the
+// average score of the functions, FileScore also weighs in the worst one, and Grade is the grade of
+// FileScore, the only grade of a file.
+type Report struct {
+	SchemaVersion    int                     `json:"schemaVersion"`
+	ZedsVersion      string                  `json:"zedsVersion"`
+	File             string                  `json:"file"`
+	BuildConstraint  string                  `json:"buildConstraint,omitempty"`
+	CommentDensity   float64                 `json:"commentDensity"`
+	Score            float64                 `json:"score"`
+	FileScore        float64                 `json:"fileScore"`
+	Grade            string                  `json:"grade"`
+	TechnicalDebt    TechnicalDebt           `json:"technicalDebt"`
+	Functions        []analyzer.MethodResult `json:"functions"`
+	Types            []analyzer.TypeResult   `json:"types,omitempty"`
+	Imports          analyzer.ImportProfile  `json:"imports"`
+	DebtMarkers      []analyzer.DebtMarker   `json:"debtMarkers,omitempty"`
+	LintFindings     []LintFinding           `json:"lintFindings,omitempty"`
+	BudgetViolations []BudgetViolation       `json:"budgetViolations,omitempty"`
+	Summary          RunSummary              `json:"summary"`
+}
+
+// NewReport builds the report envelope for the results of a single file
+func NewReport(file string, results []analyzer.MethodResult, commentDensity float64, cfg *Config) Report {
+	if results == nil {
+		results = []analyzer.MethodResult{}
+	}
+	score := CalculateScore(results, cfg)
+	fileScore := CalculateFileScore(results, cfg)
+	return Report{
+		SchemaVersion:  ReportSchemaVersion,
+		ZedsVersion:    GetVersion(),
+		File:           file,
+		CommentDensity: commentDensity,
+		Score:          score,
+		FileScore:      fileScore,
+		Grade:          GetGradeForScore(fileScore, cfg),
+		TechnicalDebt:  EstimateTechnicalDebt(results, cfg),
+		Functions:      results,
+	}
+}
+
+// writeJSON writes a value to stdout as indented JSON
+func writeJSON(v interface{}) error {
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// FunctionRecord is one line of `analyze --format ndjson` output: the metrics of a function and its file.
+type FunctionRecord struct {
+	File string `json:"file"`
+	analyzer.MethodResult
+}
+
+// writeNDJSON writes one compact JSON object per function to stdout, each on its own line. Paths
+// are relative to the working directory, as in the summary record, whether the file was found by
+// directory or by package analysis.
+func writeNDJSON(file string, results []analyzer.MethodResult) error {
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	file = reportURI(file)
+	for _, res := range results {
+		if err := enc.Encode(FunctionRecord{File: file, MethodResult: res}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileResults pairs an analyzed file with the results of its functions
+type fileResults struct {
+	file    string
+	results []analyzer.MethodResult
+}
+
+// collectedFormats lists the output formats written once, for all analyzed files, at the end of a run
+var collectedFormats = []string{"markdown", "sarif", "junit", "gitlab", "github-annotations", "teamcity", "sonar", "tap", "table"}
+
+// writeFileReports writes the results of all analyzed files to stdout in the collected format selected by opts
+func writeFileReports(files []fileResults, cfg *Config, opts analyzeOptions) error {
+	switch opts.format {
+	case "markdown":
+		_, err := io.WriteString(stdout, buildMarkdown(files, cfg, opts.markdownAll))
+		return err
+	case "sarif":
+		return writeJSON(buildSARIF(files, cfg))
+	case "junit":
+		return writeXML(buildJUnit(files, cfg))
+	case "gitlab":
+		return writeJSON(buildGitLab(files, cfg))
+	case "github-annotations":
+		_, err := io.WriteString(stdout, buildGitHubAnnotations(files, cfg))
+		return err
+	case "teamcity":
+		_, err := io.WriteString(stdout, buildTeamCity(files, cfg))
+		return err
+	case "sonar":
+		return writeJSON(buildSonar(files, cfg))
+	case "tap":
+		_, err := io.WriteString(stdout, buildTAP(files, cfg))
+		return err
+	case "table":
+		return writeTable(files, cfg, opts)
+	}
+	return fmt.Errorf("unknown format '%s'", opts.format)
+}
+
+// relToWorkingDir returns a path relative to the working directory
+func relToWorkingDir(path string) (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(wd, path)
+}
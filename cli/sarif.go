@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"path/filepath"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// sarifSchema is the JSON schema of the SARIF 2.1.0 format
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifProperties struct {
+	Halstead analyzer.HalsteadMetrics `json:"halstead"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// sarifLevel maps a violation severity to a SARIF result level
+func sarifLevel(severity Severity) string {
+	if severity == SeverityRed {
+		return "error"
+	}
+	return "warning"
+}
+
+// reportURI returns a file path as a forward-slash URI, relative to the working directory when possible
+func reportURI(file string) string {
+	if filepath.IsAbs(file) {
+		if rel, err := relToWorkingDir(file); err == nil {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// buildSARIF maps the threshold violations of the analyzed files to a SARIF 2.1.0 log
+func buildSARIF(files []fileResults, cfg *Config) sarifLog {
+	driver := sarifDriver{Name: "zeds", Version: GetVersion(), InformationURI: "https://github.com/fatihaydin9/zeds"}
+	ruleIndex := map[string]int{}
+	for _, metric := range thresholdMetrics {
+		for _, severity := range []Severity{SeverityYellow, SeverityRed} {
+			id := ruleID(metric, severity)
+			ruleIndex[id] = len(driver.Rules)
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:                   id,
+				ShortDescription:     sarifMessage{Text: ruleDescription(metric, severity)},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(severity)},
+			})
+		}
+	}
+
+	results := []sarifResult{}
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			results = append(results, sarifResult{
+				RuleID:    v.ruleID(),
+				RuleIndex: ruleIndex[v.ruleID()],
+				Level:     sarifLevel(v.severity),
+				Message:   sarifMessage{Text: v.message()},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: reportURI(v.file)},
+					Region:           sarifRegion{StartLine: v.function.Line, EndLine: v.function.EndLine},
+				}}},
+				Properties: sarifProperties{Halstead: v.function.Halstead},
+			})
+		}
+	}
+	return sarifLog{Version: "2.1.0", Schema: sarifSchema, Runs: []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}}}
+}
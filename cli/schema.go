@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ReportSchema returns the JSON Schema (draft 2020-12) of the JSON report. It is generated
+// from the Report struct so that it always matches the `--format json` output.
+func ReportSchema() map[string]interface{} {
+	defs := make(map[string]interface{})
+	schema := structSchema(reflect.TypeOf(Report{}), defs)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = fmt.Sprintf("Zeds report (schema version %d)", ReportSchemaVersion)
+	schema["$defs"] = defs
+	// A report of another version is rejected by its schemaVersion rather than by a field it lacks.
+	properties := schema["properties"].(map[string]interface{})
+	properties["schemaVersion"] = map[string]interface{}{"type": "integer", "const": ReportSchemaVersion}
+	return schema
+}
+
+// typeSchema returns the schema of a Go type. Struct types are registered in defs and referenced.
+func typeSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), defs)
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = nil // reserve the name to stop recursion
+			defs[t.Name()] = structSchema(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema returns the object schema of a struct type, following its json tags
+func structSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type, defs)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// handleSchemaCommand prints the JSON Schema of the JSON report
+func handleSchemaCommand(args []string) {
+	parseCommandOptions(newFlagSet("schema", ""), args[1:])
+	if err := writeJSON(ReportSchema()); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error writing schema: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+}
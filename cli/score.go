@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// metricScore converts a threshold color into points: green 100, yellow 50, red 0.
+func metricScore(color string) float64 {
+	switch color {
+	case ColorGreen:
+		return 100
+	case ColorYellow:
+		return 50
+	}
+	return 0
+}
+
+// CalculateScore returns the composite quality score (0-100) for a set of functions.
+// Each function scores the average of its cyclomatic, length and MI points and of the points
+// of its severity, and the composite score is the average over all functions.
+func CalculateScore(results []analyzer.MethodResult, cfg *Config) float64 {
+	if len(results) == 0 {
+		return 100
+	}
+	total := 0.0
+	for _, res := range results {
+		total += functionScore(res, cfg)
+	}
+	return total / float64(len(results))
+}
+
+// functionScore returns the average of the cyclomatic, length, MI and severity points of a
+// function. The severity covers every threshold metric, so that a function violating any of
+// them, e.g. with too many parameters, cannot score full points.
+func functionScore(res analyzer.MethodResult, cfg *Config) float64 {
+	return (metricScore(GetColorForCyclomatic(res.Cyclomatic, cfg)) +
+		metricScore(GetColorForLength(res, cfg)) +
+		metricScore(GetColorForMI(res.MaintainabilityIndex, cfg)) +
+		metricScore(GetSeverity(res, cfg).Color())) / 4
+}
+
+// CalculateFileScore returns the score a file is graded on: the average of its composite score
+// and the score of its worst function, so that one very bad function drags the grade of a file
+// down even among many good ones.
+func CalculateFileScore(results []analyzer.MethodResult, cfg *Config) float64 {
+	if len(results) == 0 {
+		return 100
+	}
+	worst := 100.0
+	for _, res := range results {
+		worst = min(worst, functionScore(res, cfg))
+	}
+	return (CalculateScore(results, cfg) + worst) / 2
+}
+
+// fileHeading returns the heading of a file's section with its grade
+func fileHeading(file string, results []analyzer.MethodResult, cfg *Config) string {
+	score := CalculateFileScore(results, cfg)
+	return Bold + ColorCyan + "File: " + file + ColorReset + " " + GetColorForScore(score, cfg) + "[" + GetGradeForScore(score, cfg) + "]" + ColorReset
+}
+
+// sortedGrades returns the grades ordered from the highest band to the lowest.
+func sortedGrades(grades []ScoreGrade) []ScoreGrade {
+	sorted := append([]ScoreGrade(nil), grades...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Min > sorted[j].Min })
+	return sorted
+}
+
+// gradeIndex returns the position of the band containing the score, counted from the highest band, or -1.
+func gradeIndex(score float64, cfg *Config) int {
+	for i, g := range sortedGrades(cfg.ScoreGrades) {
+		if score >= g.Min && (score < g.Max || (i == 0 && score == g.Max)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetGradeForScore returns the letter grade label for a quality score
+func GetGradeForScore(score float64, cfg *Config) string {
+	idx := gradeIndex(score, cfg)
+	if idx < 0 {
+		return "N/A"
+	}
+	return sortedGrades(cfg.ScoreGrades)[idx].Label
+}
+
+// GetColorForScore returns the color based on the grade band of the score:
+// green for the highest band, red for the lowest and yellow in between
+func GetColorForScore(score float64, cfg *Config) string {
+	idx := gradeIndex(score, cfg)
+	switch {
+	case idx == 0:
+		return ColorGreen
+	case idx < 0 || idx == len(cfg.ScoreGrades)-1:
+		return ColorRed
+	}
+	return ColorYellow
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// Severity ranks how badly a function breaches the configured thresholds.
+type Severity int
+
+const (
+	SeverityGreen Severity = iota
+	SeverityYellow
+	SeverityRed
+)
+
+// String returns the upper-case name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityRed:
+		return "RED"
+	case SeverityYellow:
+		return "YELLOW"
+	}
+	return "GREEN"
+}
+
+// Color returns the terminal color of the severity
+func (s Severity) Color() string {
+	switch s {
+	case SeverityRed:
+		return ColorRed
+	case SeverityYellow:
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
+// severityOfColor converts a threshold color into a severity
+func severityOfColor(color string) Severity {
+	switch color {
+	case ColorRed:
+		return SeverityRed
+	case ColorYellow:
+		return SeverityYellow
+	}
+	return SeverityGreen
+}
+
+// GetSeverity returns the overall severity of a function: the severity of its worst threshold
+// violation, green if it has none. Every report, gate and score judges functions by it.
+func GetSeverity(res analyzer.MethodResult, cfg *Config) Severity {
+	worst := SeverityGreen
+	for _, metric := range thresholdMetrics {
+		if !metric.checked(cfg) {
+			continue
+		}
+		if severity := severityOfColor(metric.color(res, cfg)); severity > worst {
+			worst = severity
+		}
+	}
+	return worst
+}
+
+// dominantRatio returns how far the function's worst metric is from its high threshold, relative to it.
+// Values above 1 mean the threshold is breached. Metrics whose high threshold is disabled are skipped.
+func dominantRatio(res analyzer.MethodResult, cfg *Config) float64 {
+	ratio := 0.0
+	for _, metric := range thresholdMetrics {
+		_, high := metric.limits(cfg)
+		if high <= 0 || !metric.checked(cfg) {
+			continue
+		}
+		if metric.lowerIsWorse {
+			ratio = math.Max(ratio, high/math.Max(metric.value(res), 1))
+		} else {
+			ratio = math.Max(ratio, metric.value(res)/high)
+		}
+	}
+	return ratio
+}
+
+// printGroupedResults prints the results in RED, YELLOW and GREEN sections, worst functions first
+func printGroupedResults(results []analyzer.MethodResult, cfg *Config, opts analyzeOptions) {
+	groups := make(map[Severity][]analyzer.MethodResult)
+	for _, res := range results {
+		severity := GetSeverity(res, cfg)
+		groups[severity] = append(groups[severity], res)
+	}
+
+	for _, severity := range []Severity{SeverityRed, SeverityYellow, SeverityGreen} {
+		group := groups[severity]
+		if len(group) == 0 {
+			continue
+		}
+		// Results ranked with --sort keep their order within each group.
+		if !ranksResults(opts) {
+			sort.SliceStable(group, func(i, j int) bool {
+				return dominantRatio(group[i], cfg) > dominantRatio(group[j], cfg)
+			})
+		}
+		fmt.Fprintln(stdout, Bold+severity.Color()+fmt.Sprintf("%s (%d)", severity, len(group))+ColorReset)
+		fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+		for _, res := range group {
+			printMethodResult(res, cfg, opts)
+		}
+		fmt.Fprintln(stdout)
+	}
+}
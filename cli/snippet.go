@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// analyzeSnippet parses a code snippet given on the command line. A snippet starting with a package
+// clause is parsed as a complete file; otherwise it is tried as top-level declarations and then as a
+// bare function body. When both fail, the declaration parse error is reported.
+func analyzeSnippet(snippet string, cfg *Config) ([]analyzer.MethodResult, float64, error) {
+	if strings.HasPrefix(strings.TrimSpace(snippet), "package ") {
+		return analyzer.AnalyzeSource("snippet.go", []byte(snippet), cfg.AnalyzerOptions())
+	}
+
+	results, density, err := analyzer.AnalyzeSource("snippet.go", []byte("package snippet\n\n"+snippet), cfg.AnalyzerOptions())
+	if err == nil {
+		return results, density, nil
+	}
+	body := "package snippet\n\nfunc snippet() {\n" + snippet + "\n}\n"
+	if bodyResults, bodyDensity, bodyErr := analyzer.AnalyzeSource("snippet.go", []byte(body), cfg.AnalyzerOptions()); bodyErr == nil {
+		return bodyResults, bodyDensity, nil
+	}
+	return nil, 0, err
+}
+
+// handleSnippetCommand analyzes a function passed directly as a string argument
+func handleSnippetCommand(args []string) {
+	flags := newFlagSet("snippet", "'<go code>'")
+	// The code may contain arguments that look like options, such as -1.
+	flags.stopAtArgument = true
+	code := parseCommandArgs(flags, args[1:])
+	if len(code) == 0 {
+		failUsage(flags, fmt.Errorf("no code specified"))
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+
+	results, commentDensity, err := analyzeSnippet(strings.Join(code, " "), cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error parsing snippet: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+
+	printHeader()
+	if len(results) == 0 {
+		fmt.Fprintln(stdout, ColorRed+"No functions found in the snippet."+ColorReset)
+		return
+	}
+	classifyResults(results, cfg)
+	printAnalysisResults(results, commentDensity*100, cfg, analyzeOptions{})
+	printFooter()
+}
@@ -0,0 +1,79 @@
+package cli
+
+// sonarReport is a SonarQube generic external issue report (SonarQube 10.3 and later)
+type sonarReport struct {
+	Rules  []sonarRule  `json:"rules"`
+	Issues []sonarIssue `json:"issues"`
+}
+
+type sonarRule struct {
+	ID                 string        `json:"id"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	EngineID           string        `json:"engineId"`
+	CleanCodeAttribute string        `json:"cleanCodeAttribute"`
+	Type               string        `json:"type"`
+	Impacts            []sonarImpact `json:"impacts"`
+}
+
+type sonarImpact struct {
+	SoftwareQuality string `json:"softwareQuality"`
+	Severity        string `json:"severity"`
+}
+
+type sonarIssue struct {
+	RuleID          string        `json:"ruleId"`
+	PrimaryLocation sonarLocation `json:"primaryLocation"`
+}
+
+type sonarLocation struct {
+	Message   string         `json:"message"`
+	FilePath  string         `json:"filePath"`
+	TextRange sonarTextRange `json:"textRange"`
+}
+
+type sonarTextRange struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// sonarSeverity maps a violation severity to the severity of a SonarQube impact
+func sonarSeverity(severity Severity) string {
+	if severity == SeverityRed {
+		return "HIGH"
+	}
+	return "MEDIUM"
+}
+
+// buildSonar maps the threshold violations of the analyzed files to a SonarQube generic issue report.
+// Only the rules of reported violations are declared, as SonarQube creates a rule for each of them.
+func buildSonar(files []fileResults, cfg *Config) sonarReport {
+	report := sonarReport{Rules: []sonarRule{}, Issues: []sonarIssue{}}
+	declared := map[string]bool{}
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			id := v.ruleID()
+			if !declared[id] {
+				declared[id] = true
+				report.Rules = append(report.Rules, sonarRule{
+					ID:                 id,
+					Name:               ruleDescription(v.metric, v.severity),
+					Description:        ruleDescription(v.metric, v.severity) + " configured for zeds.",
+					EngineID:           "zeds",
+					CleanCodeAttribute: "FOCUSED",
+					Type:               "CODE_SMELL",
+					Impacts:            []sonarImpact{{SoftwareQuality: "MAINTAINABILITY", Severity: sonarSeverity(v.severity)}},
+				})
+			}
+			report.Issues = append(report.Issues, sonarIssue{
+				RuleID: id,
+				PrimaryLocation: sonarLocation{
+					Message:   v.message(),
+					FilePath:  reportURI(v.file),
+					TextRange: sonarTextRange{StartLine: v.function.Line, EndLine: v.function.EndLine},
+				},
+			})
+		}
+	}
+	return report
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// sortKey is a metric that function listings can be sorted by with --sort, worst functions first
+type sortKey struct {
+	name  string
+	title string
+	value func(res analyzer.MethodResult) float64
+	// precision is the number of decimals the value is printed with
+	precision int
+	// lowerIsWorse is set for metrics where a low value is bad, such as the maintainability index
+	lowerIsWorse bool
+}
+
+// sortKeys lists the metrics accepted by --sort
+var sortKeys = []sortKey{
+	{"cyclomatic", "Cyclomatic Complexity", func(res analyzer.MethodResult) float64 { return float64(res.Cyclomatic) }, 0, false},
+	{"mi", "Maintainability Index", func(res analyzer.MethodResult) float64 { return res.MaintainabilityIndex }, 2, true},
+	{"loc", "Lines of Code", func(res analyzer.MethodResult) float64 { return float64(res.LOC) }, 0, false},
+	{"halstead", "Halstead Volume", func(res analyzer.MethodResult) float64 { return res.HalsteadVolume }, 2, false},
+}
+
+// defaultSortKey is the metric used when --top is given without --sort
+const defaultSortKey = "cyclomatic"
+
+// findSortKey returns the sort key with the given name
+func findSortKey(name string) (sortKey, bool) {
+	for _, key := range sortKeys {
+		if key.name == name {
+			return key, true
+		}
+	}
+	return sortKey{}, false
+}
+
+// sortKeyNames returns the names accepted by --sort
+func sortKeyNames() []string {
+	var names []string
+	for _, key := range sortKeys {
+		names = append(names, key.name)
+	}
+	return names
+}
+
+// worse reports whether function a is worse than function b by the metric of the key
+func (k sortKey) worse(a, b analyzer.MethodResult) bool {
+	if k.lowerIsWorse {
+		return k.value(a) < k.value(b)
+	}
+	return k.value(a) > k.value(b)
+}
+
+// ranksResults reports whether function listings are sorted or limited with --sort or --top
+func ranksResults(opts analyzeOptions) bool {
+	return opts.sortBy != "" || opts.top > 0
+}
+
+// rankingKey returns the sort key selected with --sort, or the default one for --top
+func rankingKey(opts analyzeOptions) sortKey {
+	name := opts.sortBy
+	if name == "" {
+		name = defaultSortKey
+	}
+	key, _ := findSortKey(name)
+	return key
+}
+
+// rankResults returns the results in the order requested with --sort, worst first, keeping only
+// the first --top of them. Without either option, the results are returned unchanged.
+func rankResults(results []analyzer.MethodResult, opts analyzeOptions) []analyzer.MethodResult {
+	if !ranksResults(opts) {
+		return results
+	}
+	key := rankingKey(opts)
+	ranked := append([]analyzer.MethodResult(nil), results...)
+	sort.SliceStable(ranked, func(i, j int) bool { return key.worse(ranked[i], ranked[j]) })
+	if opts.top > 0 && len(ranked) > opts.top {
+		ranked = ranked[:opts.top]
+	}
+	return ranked
+}
+
+// rankingNote describes the functions shown by a ranked listing of total functions
+func rankingNote(listed, total int, opts analyzeOptions) string {
+	title := rankingKey(opts).title
+	if listed < total {
+		return fmt.Sprintf("Showing the %d worst of %d functions by %s.", listed, total, title)
+	}
+	return fmt.Sprintf("Sorted by %s, worst first.", title)
+}
+
+// printWorstFunctions lists the worst functions across all analyzed files by the --sort metric,
+// keeping the first --top of them
+func printWorstFunctions(files []fileResults, opts analyzeOptions) {
+	type candidate struct {
+		file string
+		res  analyzer.MethodResult
+	}
+	var candidates []candidate
+	for _, f := range files {
+		for _, res := range f.results {
+			candidates = append(candidates, candidate{f.file, res})
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	key := rankingKey(opts)
+	sort.SliceStable(candidates, func(i, j int) bool { return key.worse(candidates[i].res, candidates[j].res) })
+	if opts.top > 0 && len(candidates) > opts.top {
+		candidates = candidates[:opts.top]
+	}
+	fmt.Fprintln(stdout, ColorCyan+"Worst Functions by "+key.title+":"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	for _, c := range candidates {
+		fmt.Fprintf(stdout, "  - %s%s%s (%s:%d): %s\n", ColorCyan, c.res.QualifiedName(), ColorReset,
+			reportURI(c.file), c.res.Line, fmt.Sprintf("%s %.*f", key.name, key.precision, key.value(c.res)))
+	}
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// RunSummary holds the aggregate statistics of an analyze run
+type RunSummary struct {
+	Files int `json:"files"`
+	// UnparsableFiles counts the files that were skipped because they do not parse
+	UnparsableFiles int             `json:"unparsableFiles"`
+	Functions       int             `json:"functions"`
+	Violations      ViolationCounts `json:"violations"`
+	Metrics         []MetricSummary `json:"metrics"`
+	// ElapsedSeconds is the time the analysis took
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// ViolationCounts counts the threshold violations of a run by severity
+type ViolationCounts struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+}
+
+// MetricSummary holds the distribution of a metric over the functions of a run. Worst is the
+// highest value, or the lowest for metrics such as the maintainability index where lower is worse.
+type MetricSummary struct {
+	Metric        string  `json:"metric"`
+	Average       float64 `json:"average"`
+	Median        float64 `json:"median"`
+	Worst         float64 `json:"worst"`
+	WorstFunction string  `json:"worstFunction,omitempty"`
+	WorstLocation string  `json:"worstLocation,omitempty"`
+}
+
+// SummaryRecord is the last line of `analyze --format ndjson` output
+type SummaryRecord struct {
+	Summary RunSummary `json:"summary"`
+}
+
+// summaryMetrics lists the threshold metrics whose distribution the summary shows
+var summaryMetrics = []string{"cyclomatic", "npath", "abc", "nesting", "maintainabilityIndex", "loc", "lloc"}
+
+// NewRunSummary computes the aggregate statistics of the results of the analyzed files
+func NewRunSummary(files []fileResults, cfg *Config, opts analyzeOptions) RunSummary {
+	summary := RunSummary{Files: len(files), Metrics: []MetricSummary{}}
+	if !opts.started.IsZero() {
+		summary.ElapsedSeconds = time.Since(opts.started).Seconds()
+	}
+	for _, f := range files {
+		summary.Functions += len(f.results)
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			if v.severity == SeverityRed {
+				summary.Violations.High++
+			} else {
+				summary.Violations.Medium++
+			}
+		}
+	}
+	if summary.Functions == 0 {
+		return summary
+	}
+	for _, key := range summaryMetrics {
+		metric, _ := findThresholdMetric(key)
+		ms := MetricSummary{Metric: key}
+		var values []float64
+		for _, f := range files {
+			for _, res := range f.results {
+				value := metric.value(res)
+				values = append(values, value)
+				if len(values) == 1 || metric.lowerIsWorse && value < ms.Worst || !metric.lowerIsWorse && value > ms.Worst {
+					ms.Worst = value
+					ms.WorstFunction = res.QualifiedName()
+					ms.WorstLocation = fmt.Sprintf("%s:%d", reportURI(f.file), res.Line)
+				}
+			}
+		}
+		for _, value := range values {
+			ms.Average += value
+		}
+		ms.Average /= float64(len(values))
+		ms.Median = analyzer.Median(values)
+		summary.Metrics = append(summary.Metrics, ms)
+	}
+	return summary
+}
+
+// printRunSummary prints the aggregate statistics of a run
+func printRunSummary(summary RunSummary) {
+	fmt.Fprintln(stdout, ColorCyan+"Summary:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	fmt.Fprintf(stdout, "Functions: %d in %d file(s)\n", summary.Functions, summary.Files)
+	if summary.UnparsableFiles > 0 {
+		fmt.Fprintf(stdout, "%sSkipped: %d file(s) that do not parse, listed with -v%s\n", ColorYellow, summary.UnparsableFiles, ColorReset)
+	}
+	fmt.Fprintf(stdout, "Violations: %s%d high%s, %s%d medium%s\n", ColorRed, summary.Violations.High, ColorReset,
+		ColorYellow, summary.Violations.Medium, ColorReset)
+	if len(summary.Metrics) > 0 {
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Metric\tAverage\tMedian\tWorst\t")
+		for _, ms := range summary.Metrics {
+			metric, _ := findThresholdMetric(ms.Metric)
+			fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%.*f\t%s (%s)\n", metric.title, ms.Average, ms.Median, metric.precision, ms.Worst,
+				ms.WorstFunction, ms.WorstLocation)
+		}
+		tw.Flush()
+	}
+	if summary.ElapsedSeconds > 0 {
+		fmt.Fprintf(stdout, "Analysis time: %s\n", time.Duration(summary.ElapsedSeconds*float64(time.Second)).Round(time.Millisecond))
+	}
+}
+
+// writeSummaryRecord writes the summary as the last line of NDJSON output
+func writeSummaryRecord(summary RunSummary) error {
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(SummaryRecord{Summary: summary})
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// tableMetrics lists the threshold metrics shown as columns of the table format, with their headers
+var tableMetrics = []struct{ header, key string }{
+	{"CC", "cyclomatic"},
+	{"NPATH", "npath"},
+	{"NEST", "nesting"},
+	{"LOC", "loc"},
+	{"LLOC", "lloc"},
+	{"ABC", "abc"},
+	{"MI", "maintainabilityIndex"},
+}
+
+// writeTable writes one row per function, with its location and main metrics in aligned columns.
+// With --sort or --top, the functions of all files are ranked together.
+func writeTable(files []fileResults, cfg *Config, opts analyzeOptions) error {
+	type row struct {
+		file string
+		res  analyzer.MethodResult
+	}
+	var rows []row
+	for _, f := range files {
+		for _, res := range f.results {
+			rows = append(rows, row{f.file, res})
+		}
+	}
+	total := len(rows)
+	if ranksResults(opts) {
+		key := rankingKey(opts)
+		sort.SliceStable(rows, func(i, j int) bool { return key.worse(rows[i].res, rows[j].res) })
+		if opts.top > 0 && len(rows) > opts.top {
+			rows = rows[:opts.top]
+		}
+	}
+
+	// Colored cells are wrapped in escape sequences of the same length in every row, including the
+	// header, so that the tabwriter, which counts them as text, still aligns the columns.
+	colored := func(color, text string) string {
+		if !stdout.color {
+			return text
+		}
+		return color + text + ColorReset
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "FUNCTION\tLOCATION")
+	for _, column := range tableMetrics {
+		fmt.Fprint(tw, "\t"+colored(ColorCyan, column.header))
+	}
+	fmt.Fprintln(tw, "\tHALSTEAD")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s:%d", r.res.QualifiedName(), reportURI(r.file), r.res.Line)
+		for _, column := range tableMetrics {
+			metric, _ := findThresholdMetric(column.key)
+			fmt.Fprint(tw, "\t"+colored(metric.color(r.res, cfg.ForFile(r.file)), fmt.Sprintf("%.*f", metric.precision, metric.value(r.res))))
+		}
+		fmt.Fprintf(tw, "\t%.2f\n", r.res.HalsteadVolume)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if ranksResults(opts) {
+		_, err := fmt.Fprintln(stdout, rankingNote(len(rows), total, opts))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tapDescriptionEscaper escapes a TAP test description, in which "#" would start a directive
+var tapDescriptionEscaper = strings.NewReplacer("\\", "\\\\", "#", "\\#")
+
+// tapYAMLString quotes a string for a TAP YAML diagnostic block
+func tapYAMLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// buildTAP writes each function as a TAP version 13 test. A test is not ok when a metric reaches
+// its high threshold, like a failing JUnit test case, and its YAML block lists the violations.
+// Medium violations are written as diagnostic comments after the test line.
+func buildTAP(files []fileResults, cfg *Config) string {
+	var sb strings.Builder
+	total := 0
+	for _, f := range files {
+		total += len(f.results)
+	}
+	sb.WriteString("TAP version 13\n")
+	fmt.Fprintf(&sb, "1..%d\n", total)
+	n := 0
+	for _, f := range files {
+		uri := reportURI(f.file)
+		for _, res := range f.results {
+			n++
+			high, medium := functionViolations(f.file, res, cfg)
+			status := "ok"
+			if len(high) > 0 {
+				status = "not ok"
+			}
+			fmt.Fprintf(&sb, "%s %d - %s\n", status, n, tapDescriptionEscaper.Replace(uri+": "+res.QualifiedName()))
+			if len(high) > 0 {
+				sb.WriteString("  ---\n")
+				sb.WriteString("  message: " + tapYAMLString(fmt.Sprintf("%d metric(s) reach a high threshold", len(high))) + "\n")
+				sb.WriteString("  severity: fail\n")
+				sb.WriteString("  file: " + tapYAMLString(uri) + "\n")
+				fmt.Fprintf(&sb, "  line: %d\n", res.Line)
+				sb.WriteString("  violations:\n")
+				for _, msg := range high {
+					sb.WriteString("    - " + tapYAMLString(msg) + "\n")
+				}
+				sb.WriteString("  ...\n")
+			}
+			for _, msg := range medium {
+				sb.WriteString("# " + msg + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// teamCityEscaper escapes the attribute values of a TeamCity service message
+var teamCityEscaper = strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]")
+
+// teamCitySeverity maps a violation severity to a TeamCity inspection severity
+func teamCitySeverity(severity Severity) string {
+	if severity == SeverityRed {
+		return "ERROR"
+	}
+	return "WARNING"
+}
+
+// teamCityMessage formats a service message, e.g. "##teamcity[inspection typeId='zeds/loc-high' ...]",
+// from alternating attribute names and values
+func teamCityMessage(name string, attrs ...string) string {
+	var sb strings.Builder
+	sb.WriteString("##teamcity[" + name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		sb.WriteString(" " + attrs[i] + "='" + teamCityEscaper.Replace(attrs[i+1]) + "'")
+	}
+	sb.WriteString("]\n")
+	return sb.String()
+}
+
+// buildTeamCity writes the threshold violations as TeamCity inspection service messages. Each
+// rule is declared with an inspectionType message before its first inspection.
+func buildTeamCity(files []fileResults, cfg *Config) string {
+	var sb strings.Builder
+	declared := map[string]bool{}
+	for _, f := range files {
+		for _, v := range findViolations(f.file, f.results, cfg) {
+			id := v.ruleID()
+			if !declared[id] {
+				declared[id] = true
+				sb.WriteString(teamCityMessage("inspectionType", "id", id, "name", id,
+					"category", "Complexity", "description", ruleDescription(v.metric, v.severity)))
+			}
+			sb.WriteString(teamCityMessage("inspection", "typeId", id, "message", v.message(),
+				"file", reportURI(v.file), "line", fmt.Sprint(v.function.Line), "SEVERITY", teamCitySeverity(v.severity)))
+		}
+	}
+	return sb.String()
+}
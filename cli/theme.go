@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Theme selects the colors of the terminal output. The color constants such as ColorRed name the
+// role of a color, e.g. red for high threshold violations; a theme maps each of them to the escape
+// sequence the terminal receives.
+type Theme struct {
+	// Name is the built-in theme the colors start from: dark, light or mono
+	Name string `json:"name"`
+	// Colors overrides colors of the theme by name, with a color name such as "red" or "bright-red",
+	// a 256-color palette index such as "208", a truecolor value such as "#ff8700", or "none"
+	Colors map[string]string `json:"colors"`
+}
+
+// Built-in themes
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+	ThemeMono  = "mono"
+)
+
+// themeColors maps the names of the themeable colors to their constants
+var themeColors = map[string]string{
+	"red":     ColorRed,
+	"green":   ColorGreen,
+	"yellow":  ColorYellow,
+	"blue":    ColorBlue,
+	"magenta": ColorMagenta,
+	"cyan":    ColorCyan,
+	"white":   ColorWhite,
+}
+
+// builtinThemes lists the colors each built-in theme changes. The dark theme uses the terminal's
+// own palette; the light theme replaces the colors that are hard to read on a light background;
+// the mono theme keeps only bold and italic text.
+var builtinThemes = map[string]map[string]string{
+	ThemeDark:  {},
+	ThemeLight: {"yellow": "130", "cyan": "31", "white": "black"},
+	ThemeMono: {"red": "none", "green": "none", "yellow": "none", "blue": "none",
+		"magenta": "none", "cyan": "none", "white": "none"},
+}
+
+// basicColors lists the colors of the 16-color palette in the order of their ANSI codes
+var basicColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// themeSequences maps the escape sequence of each color constant to the sequence of the theme in
+// use. It is nil when the theme changes no color.
+var themeSequences map[string]string
+
+// Validate checks the theme name and colors
+func (t Theme) Validate() error {
+	_, err := t.sequences()
+	return err
+}
+
+// sequences returns the escape sequence of each color constant the theme changes
+func (t Theme) sequences() (map[string]string, error) {
+	base, ok := builtinThemes[t.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme.name %q. Valid themes: %s, %s, %s", t.Name, ThemeDark, ThemeLight, ThemeMono)
+	}
+	specs := make(map[string]string)
+	for name, spec := range base {
+		specs[name] = spec
+	}
+	for name, spec := range t.Colors {
+		if _, ok := themeColors[name]; !ok {
+			return nil, fmt.Errorf("unknown theme.colors entry %q. Valid colors: %s", name, strings.Join(themeColorNames(), ", "))
+		}
+		specs[name] = spec
+	}
+	sequences := make(map[string]string, len(specs))
+	for name, spec := range specs {
+		sequence, err := colorSequence(spec)
+		if err != nil {
+			return nil, fmt.Errorf("theme.colors.%s: %w", name, err)
+		}
+		sequences[themeColors[name]] = sequence
+	}
+	return sequences, nil
+}
+
+// themeColorNames returns the names of the themeable colors, sorted
+func themeColorNames() []string {
+	names := make([]string, 0, len(themeColors))
+	for name := range themeColors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// colorSequence returns the escape sequence that selects the foreground color of a color spec.
+// Truecolor values are approximated with the 256-color palette unless the COLORTERM environment
+// variable announces a truecolor terminal.
+func colorSequence(spec string) (string, error) {
+	if spec == "none" {
+		return "", nil
+	}
+	for i, name := range basicColors {
+		switch spec {
+		case name:
+			return fmt.Sprintf("\x1b[%dm", 30+i), nil
+		case "bright-" + name:
+			return fmt.Sprintf("\x1b[%dm", 90+i), nil
+		}
+	}
+	if index, err := strconv.Atoi(spec); err == nil && index >= 0 && index <= 255 {
+		return fmt.Sprintf("\x1b[38;5;%dm", index), nil
+	}
+	if len(spec) == 7 && spec[0] == '#' {
+		if rgb, err := strconv.ParseUint(spec[1:], 16, 32); err == nil {
+			r, g, b := int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)
+			if colorTerm := os.Getenv("COLORTERM"); colorTerm == "truecolor" || colorTerm == "24bit" {
+				return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", r, g, b), nil
+			}
+			return fmt.Sprintf("\x1b[38;5;%dm", paletteIndex(r, g, b)), nil
+		}
+	}
+	return "", fmt.Errorf("invalid color %q: expected a color name such as red or bright-red, a palette index from 0 to 255, #rrggbb or none", spec)
+}
+
+// paletteIndex returns the color of the 6×6×6 cube of the 256-color palette closest to an RGB color
+func paletteIndex(r, g, b int) int {
+	// The levels of the cube are 0, 95, 135, 175, 215 and 255.
+	level := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	return 16 + 36*level(r) + 6*level(g) + level(b)
+}
+
+// applyTheme makes colored output use the colors of the theme set in the configuration file, if
+// there is a valid one. An invalid configuration is left for the command to report.
+func applyTheme() {
+	themeSequences = nil
+	cfg, err := LoadConfig()
+	if err != nil {
+		return
+	}
+	sequences, err := cfg.Theme.sequences()
+	if err != nil || len(sequences) == 0 {
+		return
+	}
+	themeSequences = sequences
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// analyzeTypes returns the metrics of the struct and interface types of a file, filtered like its functions
+func analyzeTypes(name string, src []byte, cfg *Config, opts analyzeOptions) []analyzer.TypeResult {
+	analyzerOpts := cfg.AnalyzerOptions()
+	analyzerOpts.ParserMode |= opts.parserMode
+	types, err := analyzer.AnalyzeTypes(name, src, analyzerOpts)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error during analysis: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if !reportsAllFunctions(opts) {
+		return nil
+	}
+	return types
+}
+
+// reportsAllFunctions reports whether every function is analyzed rather than a subset. Type
+// metrics summarize all methods, so they are left out when only some functions are reported.
+func reportsAllFunctions(opts analyzeOptions) bool {
+	return !opts.exportedOnly && opts.match == nil && opts.changedLines == nil && !filtersThresholds(opts)
+}
+
+// printTypeResults prints the metrics of the struct and interface types of a file
+func printTypeResults(types []analyzer.TypeResult, cfg *Config) {
+	if len(types) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, ColorCyan+"Type Results:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	for _, t := range types {
+		fmt.Fprintln(stdout, "Type:", ColorCyan+t.Name+ColorReset, "("+t.Kind+")")
+		if t.Kind == analyzer.KindInterface {
+			fmt.Fprintln(stdout, "  - Methods:", GetColorForInterfaceMethods(t.Methods, cfg), t.Methods, ColorReset)
+			continue
+		}
+		fmt.Fprintln(stdout, "  - Fields:", GetColorForStructFields(t.Fields, cfg), t.Fields, ColorReset)
+		if t.Methods == 0 {
+			continue
+		}
+		fmt.Fprintln(stdout, "  - Methods:", t.Methods)
+		fmt.Fprintln(stdout, "  - Lack of Cohesion (LCOM):", GetColorForLCOM(t.LCOM, cfg), fmt.Sprintf("%.2f", t.LCOM), ColorReset)
+		fmt.Fprintln(stdout, "  - Weighted Methods (WMC):", GetColorForWMC(t.WMC, cfg), t.WMC, ColorReset)
+	}
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+}
+
+// heaviestTypes is the number of types listed in the weighted methods summary
+const heaviestTypes = 5
+
+// typeWeight holds the weighted methods of a receiver type across the files of its directory
+type typeWeight struct {
+	dir, name    string
+	methods, wmc int
+}
+
+// printWeightedMethods lists the types whose methods, summed over all files of their directory,
+// reach the medium WMC threshold, heaviest first
+func printWeightedMethods(files []fileResults, cfg *Config) {
+	weights := make(map[[2]string]*typeWeight)
+	var order []*typeWeight
+	for _, f := range files {
+		dir := filepath.Dir(f.file)
+		for _, res := range f.results {
+			if res.Receiver == "" {
+				continue
+			}
+			key := [2]string{dir, res.Receiver}
+			w, ok := weights[key]
+			if !ok {
+				w = &typeWeight{dir: dir, name: res.Receiver}
+				weights[key] = w
+				order = append(order, w)
+			}
+			w.methods++
+			w.wmc += res.Cyclomatic
+		}
+	}
+	var heavy []*typeWeight
+	for _, w := range order {
+		if GetColorForWMC(w.wmc, cfg) != ColorGreen {
+			heavy = append(heavy, w)
+		}
+	}
+	if len(heavy) == 0 {
+		return
+	}
+	sort.SliceStable(heavy, func(i, j int) bool { return heavy[i].wmc > heavy[j].wmc })
+	if len(heavy) > heaviestTypes {
+		heavy = heavy[:heaviestTypes]
+	}
+	fmt.Fprintln(stdout, ColorCyan+"Weighted Methods per Type:"+ColorReset)
+	fmt.Fprintln(stdout, ColorCyan+"------------------------------------------"+ColorReset)
+	for _, w := range heavy {
+		fmt.Fprintf(stdout, "  - %s%s%s (%s): WMC %s%d%s over %d method(s)\n", ColorCyan, w.name, ColorReset,
+			reportURI(w.dir), GetColorForWMC(w.wmc, cfg), w.wmc, ColorReset, w.methods)
+	}
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Version, Commit and BuildDate describe the zeds build. They can be set at build time with
+// -ldflags "-X github.com/fatihaydin9/zeds/cli.Version=v1.2.3 -X github.com/fatihaydin9/zeds/cli.Commit=abc1234
+// -X github.com/fatihaydin9/zeds/cli.BuildDate=2024-01-02T15:04:05Z".
+var (
+	Version   = ""
+	Commit    = ""
+	BuildDate = ""
+)
+
+// GetVersion returns the zeds version, falling back to the module version recorded by `go install`.
+func GetVersion() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// GetCommit returns the commit zeds was built from, falling back to the revision recorded by the
+// Go toolchain when building in a repository, with a "-dirty" suffix for uncommitted changes.
+// It returns an empty string when the commit is unknown.
+func GetCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	commit := buildSetting("vcs.revision")
+	if commit != "" && buildSetting("vcs.modified") == "true" {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// GetBuildDate returns when zeds was built, falling back to the time of the commit recorded by the
+// Go toolchain. It returns an empty string when the date is unknown.
+func GetBuildDate() string {
+	if BuildDate != "" {
+		return BuildDate
+	}
+	return buildSetting("vcs.time")
+}
+
+// buildSetting returns a setting recorded in the binary by the Go toolchain, or an empty string
+func buildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == key {
+			return setting.Value
+		}
+	}
+	return ""
+}
+
+// VersionInfo is the output of `zeds version --format json`
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+	// SchemaVersion is the version of the JSON report format, see ReportSchemaVersion
+	SchemaVersion int `json:"schemaVersion"`
+}
+
+// handleVersionCommand prints the version of zeds, its build metadata and the version of the JSON
+// report format, so that bug reports and report consumers can pin the behavior they rely on
+func handleVersionCommand(args []string) {
+	format := "text"
+	flags := newFlagSet("version", "")
+	flags.stringVar(&format, "format", "", "write the version in the `format`: text, json")
+	parseCommandOptions(flags, args[1:])
+	if format != "text" && format != "json" {
+		failUsage(flags, fmt.Errorf("unknown format '%s'", format))
+	}
+
+	info := VersionInfo{
+		Version:       GetVersion(),
+		Commit:        GetCommit(),
+		BuildDate:     GetBuildDate(),
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		SchemaVersion: ReportSchemaVersion,
+	}
+	if format == "json" {
+		if err := writeJSON(info); err != nil {
+			fmt.Fprintln(stderr, "Error writing version: "+err.Error())
+			exit(exitError)
+		}
+		return
+	}
+	fmt.Fprintln(stdout, "zeds "+info.Version)
+	fmt.Fprintln(stdout, "  Commit:         "+valueOrUnknown(info.Commit))
+	fmt.Fprintln(stdout, "  Build date:     "+valueOrUnknown(info.BuildDate))
+	fmt.Fprintln(stdout, "  Go version:     "+info.GoVersion+" "+info.Platform)
+	fmt.Fprintf(stdout, "  Report schema:  %d\n", info.SchemaVersion)
+}
+
+// valueOrUnknown returns the value, or "unknown" when it is empty
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
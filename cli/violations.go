@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+)
+
+// thresholdMetric is a function metric checked against the configured thresholds
+type thresholdMetric struct {
+	// key is the metric's name in the config file and in rule IDs
+	key   string
+	title string
+	value func(analyzer.MethodResult) float64
+	color func(analyzer.MethodResult, *Config) string
+	// limits returns the medium and high thresholds of the metric
+	limits func(*Config) (float64, float64)
+	// lowerIsWorse is set for metrics such as MI, where values below the thresholds are violations
+	lowerIsWorse bool
+	precision    int
+	// applies reports whether the configuration checks the metric at all; nil means always
+	applies func(*Config) bool
+}
+
+// checked reports whether a function breaching the thresholds of the metric is a violation.
+// Only the length metric selected by functionLength is checked, so that a long function is
+// reported and charged once rather than for both its LOC and its LLOC.
+func (m thresholdMetric) checked(cfg *Config) bool {
+	return m.applies == nil || m.applies(cfg)
+}
+
+// thresholdMetrics lists the metrics that report formats turn into threshold violations
+var thresholdMetrics = []thresholdMetric{
+	{key: "cyclomatic", title: "Cyclomatic complexity",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.Cyclomatic) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForCyclomatic(r.Cyclomatic, c) },
+		limits: func(c *Config) (float64, float64) { return c.Cyclomatic.Medium, c.Cyclomatic.High }},
+	{key: "npath", title: "NPath complexity",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.NPath) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForNPath(r.NPath, c) },
+		limits: func(c *Config) (float64, float64) { return c.NPath.Medium, c.NPath.High }},
+	{key: "abc", title: "ABC size",
+		value:     func(r analyzer.MethodResult) float64 { return r.ABC.Score },
+		color:     func(r analyzer.MethodResult, c *Config) string { return GetColorForABC(r.ABC.Score, c) },
+		limits:    func(c *Config) (float64, float64) { return c.ABC.Medium, c.ABC.High },
+		precision: 2},
+	{key: "nesting", title: "Nesting depth",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.MaxNesting) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForNesting(r.MaxNesting, c) },
+		limits: func(c *Config) (float64, float64) { return c.Nesting.Medium, c.Nesting.High }},
+	{key: "maintainabilityIndex", title: "Maintainability index",
+		value:        func(r analyzer.MethodResult) float64 { return r.MaintainabilityIndex },
+		color:        func(r analyzer.MethodResult, c *Config) string { return GetColorForMI(r.MaintainabilityIndex, c) },
+		limits:       func(c *Config) (float64, float64) { return c.MaintainabilityIndex.Medium, c.MaintainabilityIndex.Low },
+		lowerIsWorse: true, precision: 2},
+	{key: "loc", title: "Lines of code",
+		value:   func(r analyzer.MethodResult) float64 { return float64(r.LOC) },
+		color:   func(r analyzer.MethodResult, c *Config) string { return GetColorForLOC(r.LOC, c) },
+		limits:  func(c *Config) (float64, float64) { return c.LOC.Medium, c.LOC.High },
+		applies: func(c *Config) bool { return c.FunctionLength != FunctionLengthLLOC }},
+	{key: "lloc", title: "Logical lines of code",
+		value:   func(r analyzer.MethodResult) float64 { return float64(r.LLOC) },
+		color:   func(r analyzer.MethodResult, c *Config) string { return GetColorForLLOC(r.LLOC, c) },
+		limits:  func(c *Config) (float64, float64) { return c.LLOC.Medium, c.LLOC.High },
+		applies: func(c *Config) bool { return c.FunctionLength == FunctionLengthLLOC }},
+	{key: "indentation", title: "Indentation complexity",
+		value: func(r analyzer.MethodResult) float64 { return float64(r.Indentation) },
+		color: func(r analyzer.MethodResult, c *Config) string {
+			return GetColorForIndentation(r.Indentation, c)
+		},
+		limits: func(c *Config) (float64, float64) { return c.Indentation.Medium, c.Indentation.High }},
+	{key: "localVars", title: "Local variable count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.LocalVars) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForLocalVars(r.LocalVars, c) },
+		limits: func(c *Config) (float64, float64) { return c.LocalVars.Medium, c.LocalVars.High }},
+	{key: "params", title: "Parameter count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.Params) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForParams(r.Params, c) },
+		limits: func(c *Config) (float64, float64) { return c.Params.Medium, c.Params.High }},
+	{key: "results", title: "Return value count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.Results) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForResults(r.Results, c) },
+		limits: func(c *Config) (float64, float64) { return c.Results.Medium, c.Results.High }},
+	{key: "exits", title: "Exit point count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.Exits) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForExits(r.Exits, c) },
+		limits: func(c *Config) (float64, float64) { return c.Exits.Medium, c.Exits.High }},
+	{key: "magicNumbers", title: "Magic number count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.MagicNumbers) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForMagicNumbers(r.MagicNumbers, c) },
+		limits: func(c *Config) (float64, float64) { return c.MagicNumbers.Medium, c.MagicNumbers.High }},
+	{key: "errorDensity", title: "Error handling density",
+		value: func(r analyzer.MethodResult) float64 { return r.ErrorHandling.Density },
+		color: func(r analyzer.MethodResult, c *Config) string {
+			return GetColorForErrorDensity(r.ErrorHandling.Density, c)
+		},
+		limits:    func(c *Config) (float64, float64) { return c.ErrorDensity.Medium, c.ErrorDensity.High },
+		precision: 2},
+	{key: "fanOut", title: "Fan-out",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.FanOut) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForFanOut(r.FanOut, c) },
+		limits: func(c *Config) (float64, float64) { return c.FanOut.Medium, c.FanOut.High }},
+	{key: "concurrency", title: "Concurrency weight",
+		value: func(r analyzer.MethodResult) float64 { return float64(r.Concurrency.Weight) },
+		color: func(r analyzer.MethodResult, c *Config) string {
+			return GetColorForConcurrency(r.Concurrency.Weight, c)
+		},
+		limits: func(c *Config) (float64, float64) { return c.Concurrency.Medium, c.Concurrency.High }},
+	{key: "goroutines", title: "Goroutine count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.Goroutines) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForGoroutines(r.Goroutines, c) },
+		limits: func(c *Config) (float64, float64) { return c.Goroutines.Medium, c.Goroutines.High }},
+	{key: "defers", title: "Defer count",
+		value:  func(r analyzer.MethodResult) float64 { return float64(r.Defers) },
+		color:  func(r analyzer.MethodResult, c *Config) string { return GetColorForDefers(r.Defers, c) },
+		limits: func(c *Config) (float64, float64) { return c.Defers.Medium, c.Defers.High }},
+}
+
+// findThresholdMetric returns the threshold metric with the given key
+func findThresholdMetric(key string) (thresholdMetric, bool) {
+	for _, metric := range thresholdMetrics {
+		if metric.key == key {
+			return metric, true
+		}
+	}
+	return thresholdMetric{}, false
+}
+
+// violation is a function metric that reached its medium or high threshold
+type violation struct {
+	file      string
+	function  analyzer.MethodResult
+	metric    thresholdMetric
+	severity  Severity
+	threshold float64
+}
+
+// ruleID returns the identifier of the rule the violation breaks, e.g. "zeds/cyclomatic-high"
+func (v violation) ruleID() string {
+	return ruleID(v.metric, v.severity)
+}
+
+// message describes the violation, e.g. "Cyclomatic complexity of Parse is 12 (high threshold 10)"
+func (v violation) message() string {
+	comparison := ""
+	if v.metric.lowerIsWorse {
+		comparison = "below the "
+	}
+	return fmt.Sprintf("%s of %s is %.*f (%s%s threshold %.*f)", v.metric.title, v.function.QualifiedName(),
+		v.metric.precision, v.metric.value(v.function), comparison, severityLevelName(v.severity), v.metric.precision, v.threshold)
+}
+
+// ruleID returns the rule identifier of a metric at a severity, e.g. "zeds/loc-medium"
+func ruleID(metric thresholdMetric, severity Severity) string {
+	return "zeds/" + metric.key + "-" + severityLevelName(severity)
+}
+
+// ruleDescription describes the rule of a metric at a severity, e.g. "Lines of code reaches the medium threshold"
+func ruleDescription(metric thresholdMetric, severity Severity) string {
+	verb := " reaches the "
+	if metric.lowerIsWorse {
+		verb = " falls below the "
+	}
+	return metric.title + verb + severityLevelName(severity) + " threshold"
+}
+
+// severityLevelName returns "high" for red and "medium" for yellow violations
+func severityLevelName(severity Severity) string {
+	if severity == SeverityRed {
+		return "high"
+	}
+	return "medium"
+}
+
+// findViolations returns every metric of the functions in a file that reached a threshold
+func findViolations(file string, results []analyzer.MethodResult, cfg *Config) []violation {
+	cfg = cfg.ForFile(file)
+	var violations []violation
+	for _, res := range results {
+		for _, metric := range thresholdMetrics {
+			if !metric.checked(cfg) {
+				continue
+			}
+			severity := severityOfColor(metric.color(res, cfg))
+			if severity == SeverityGreen {
+				continue
+			}
+			medium, high := metric.limits(cfg)
+			threshold := medium
+			if severity == SeverityRed {
+				threshold = high
+			}
+			violations = append(violations, violation{file: file, function: res, metric: metric, severity: severity, threshold: threshold})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatihaydin9/zeds/analyzer"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a watch waits for further changes before analyzing, since editors
+// often save a file in several writes
+const watchDebounce = 100 * time.Millisecond
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\x1b[H\x1b[2J"
+
+// handleWatchCommand re-analyzes a file every time it is saved, annotating each function with how
+// its metrics changed since the previous save. With --dir, it watches every Go file in a directory
+// and prints the changes of each saved file as a delta.
+func handleWatchCommand(args []string) {
+	var filePath, dir string
+	var recursive bool
+	flags := newFlagSet("watch", "-f <file>", "-d <dir> [-r]")
+	flags.stringVar(&filePath, "file", "f", "watch the Go `file`")
+	flags.stringVar(&dir, "dir", "d", "watch the Go files in the `directory`; a trailing /... also watches its subdirectories")
+	flags.boolVar(&recursive, "recursive", "r", "also watch the subdirectories of --dir")
+	parseCommandOptions(flags, args[1:])
+	dir, recursive = recursiveDir(dir, recursive)
+	switch {
+	case filePath == "" && dir == "":
+		failUsage(flags, fmt.Errorf("no file or directory specified"))
+	case filePath != "" && dir != "":
+		failUsage(flags, fmt.Errorf("--file and --dir cannot be combined"))
+	case recursive && dir == "":
+		failUsage(flags, fmt.Errorf("--recursive requires --dir"))
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error watching: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	exitHooks = append(exitHooks, func() { _ = watcher.Close() })
+	if dir != "" {
+		watchDirectory(watcher, filepath.Clean(dir), recursive)
+		return
+	}
+	watchFile(watcher, filepath.Clean(filePath))
+}
+
+// watchFile re-analyzes the file on every save. The directory of the file is watched rather than
+// the file itself, since some editors save by replacing the file.
+func watchFile(watcher *fsnotify.Watcher, filePath string) {
+	if _, err := os.Stat(filePath); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error watching file: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	if err := watcher.Add(filepath.Dir(filePath)); err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error watching file: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	previous, _ := watchRun(filePath, nil)
+	for {
+		changed := nextChanges(watcher)
+		if !containsString(changed, filePath) {
+			continue
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			// The file is being replaced; it is analyzed once it is back.
+			continue
+		}
+		if current, ok := watchRun(filePath, previous); ok {
+			previous = current
+		}
+	}
+}
+
+// watchRun analyzes the file once and prints the annotated results. Errors, such as a file
+// saved mid-edit that does not parse, are reported without stopping the watch.
+func watchRun(filePath string, previous map[string]analyzer.MethodResult) (map[string]analyzer.MethodResult, bool) {
+	fmt.Fprint(stdout, clearScreen)
+	printHeader()
+	fmt.Fprintln(stdout, Italic+"Watching "+filePath+" (Ctrl+C to stop) - "+time.Now().Format("15:04:05")+ItalicReset)
+	fmt.Fprintln(stdout)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		return nil, false
+	}
+	cfg = cfg.ForFile(filePath)
+	results, commentDensity, err := analyzeWatched(filePath, cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error during analysis: "+err.Error()+ColorReset)
+		return nil, false
+	}
+
+	printFileResults(results, commentDensity, 0, cfg, analyzeOptions{previous: previous})
+	printFooter()
+	return resultsByName(results), true
+}
+
+// watchDirectory analyzes the Go files of the directory once, then prints a delta of the metrics
+// of every file that is saved, created or removed. New subdirectories are watched as they appear
+// when recursive is set.
+func watchDirectory(watcher *fsnotify.Watcher, dir string, recursive bool) {
+	files, err := collectGoFiles(dir, recursive)
+	if err == nil {
+		err = addWatches(watcher, dir, recursive)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error watching directory: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+		exit(exitError)
+	}
+	// previous holds the results of the last successful analysis of each file, keyed by qualified name
+	previous := make(map[string]map[string]analyzer.MethodResult)
+	for _, path := range files {
+		if results, _, err := analyzeWatched(path, cfg); err == nil {
+			previous[path] = resultsByName(results)
+		}
+	}
+	printHeader()
+	fmt.Fprintln(stdout, Italic+fmt.Sprintf("Watching %d file(s) in %s (Ctrl+C to stop)", len(files), dir)+ItalicReset)
+	fmt.Fprintln(stdout)
+
+	for {
+		changed := nextChanges(watcher)
+		if cfg, err = LoadConfig(); err != nil {
+			fmt.Fprintln(stderr, ColorRed+"Error loading config: "+err.Error()+ColorReset)
+			continue
+		}
+		for _, path := range changed {
+			info, err := os.Stat(path)
+			switch {
+			case err == nil && info.IsDir():
+				if recursive {
+					if err := addWatches(watcher, path, true); err != nil {
+						fmt.Fprintln(stderr, ColorRed+"Error watching directory: "+err.Error()+ColorReset)
+					}
+				}
+			case !strings.HasSuffix(path, ".go"):
+			case errors.Is(err, fs.ErrNotExist):
+				if prev, ok := previous[path]; ok {
+					printDelta(path, prev, nil)
+					delete(previous, path)
+				}
+			default:
+				results, _, err := analyzeWatched(path, cfg)
+				if err != nil {
+					fmt.Fprintln(stderr, watchTime()+ColorRed+"Error analyzing "+path+": "+err.Error()+ColorReset)
+					continue
+				}
+				printDelta(path, previous[path], results)
+				previous[path] = resultsByName(results)
+			}
+		}
+	}
+}
+
+// addWatches watches dir and, when recursive is set, every directory below it
+func addWatches(watcher *fsnotify.Watcher, dir string, recursive bool) error {
+	if !recursive {
+		return watcher.Add(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		return watcher.Add(path)
+	})
+}
+
+// nextChanges waits until watched paths change and returns them, sorted. Changes that follow each
+// other within watchDebounce are returned together, so that a save is analyzed once. A failure of
+// the watcher ends the command.
+func nextChanges(watcher *fsnotify.Watcher) []string {
+	changed := make(map[string]bool)
+	var quiet <-chan time.Time
+	for {
+		select {
+		case event := <-watcher.Events:
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			changed[filepath.Clean(event.Name)] = true
+			quiet = time.After(watchDebounce)
+		case err := <-watcher.Errors:
+			fmt.Fprintln(stderr, ColorRed+"Error watching: "+err.Error()+ColorReset)
+			exit(exitError)
+		case <-quiet:
+			paths := make([]string, 0, len(changed))
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			return paths
+		}
+	}
+}
+
+// analyzeWatched analyzes a watched file from disk
+func analyzeWatched(path string, cfg *Config) ([]analyzer.MethodResult, float64, error) {
+	cfg = cfg.ForFile(path)
+	fsys, name := diskFile(path)
+	results, commentDensity, err := analyzer.AnalyzeMethods(fsys, name, cfg.AnalyzerOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	classifyResults(results, cfg)
+	return results, commentDensity, nil
+}
+
+// resultsByName indexes results by the qualified name of their function
+func resultsByName(results []analyzer.MethodResult) map[string]analyzer.MethodResult {
+	byName := make(map[string]analyzer.MethodResult, len(results))
+	for _, res := range results {
+		byName[res.QualifiedName()] = res
+	}
+	return byName
+}
+
+// watchTime returns the time of a watch event as a prefix for its output
+func watchTime() string {
+	return ColorWhite + time.Now().Format("15:04:05") + ColorReset + " "
+}
+
+// printDelta prints how the functions of a file changed since the previous analysis, in the style
+// of a diff: "+" marks a new function, "-" a removed one and "~" one whose metrics changed. The
+// results are nil for a removed file.
+func printDelta(path string, previous map[string]analyzer.MethodResult, results []analyzer.MethodResult) {
+	type deltaLine struct{ mark, name, detail string }
+	var lines []deltaLine
+	for _, res := range results {
+		name := res.QualifiedName()
+		prev, ok := previous[name]
+		switch {
+		case !ok:
+			lines = append(lines, deltaLine{ColorGreen + "+", name, fmt.Sprintf("CC %d  LOC %d  MI %.2f", res.Cyclomatic, res.LOC, res.MaintainabilityIndex)})
+		case formatTrend(prev, res) != "":
+			lines = append(lines, deltaLine{ColorYellow + "~", name, formatTrend(prev, res)})
+		}
+	}
+	current := resultsByName(results)
+	var removed []string
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		lines = append(lines, deltaLine{ColorRed + "-", name, ""})
+	}
+
+	fmt.Fprintln(stdout, watchTime()+Bold+path+ColorReset)
+	if len(lines) == 0 {
+		fmt.Fprintln(stdout, Italic+"  No metric changes."+ItalicReset)
+	}
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line.name))
+	}
+	for _, line := range lines {
+		fmt.Fprintln(stdout, strings.TrimRight(fmt.Sprintf("  %s %-*s"+ColorReset+"  %s", line.mark, width, line.name, line.detail), " "))
+	}
+}
+
+// formatTrend describes how a function's metrics changed since the previous run, e.g. "↓ CC 12→9".
+// It returns an empty string when nothing changed.
+func formatTrend(prev, cur analyzer.MethodResult) string {
+	var parts []string
+	addInt := func(label string, before, after int, lowerIsBetter bool) {
+		if before != after {
+			parts = append(parts, trendPart(label, float64(before), float64(after), fmt.Sprint(before), fmt.Sprint(after), lowerIsBetter))
+		}
+	}
+	addInt("CC", prev.Cyclomatic, cur.Cyclomatic, true)
+	addInt("LOC", prev.LOC, cur.LOC, true)
+	if math.Abs(prev.MaintainabilityIndex-cur.MaintainabilityIndex) >= 0.01 {
+		parts = append(parts, trendPart("MI", prev.MaintainabilityIndex, cur.MaintainabilityIndex,
+			fmt.Sprintf("%.2f", prev.MaintainabilityIndex), fmt.Sprintf("%.2f", cur.MaintainabilityIndex), false))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// trendPart formats one metric change with an arrow for its direction, green when it improved and red otherwise
+func trendPart(label string, before, after float64, beforeText, afterText string, lowerIsBetter bool) string {
+	arrow := "↑"
+	if after < before {
+		arrow = "↓"
+	}
+	color := ColorRed
+	if (after < before) == lowerIsBetter {
+		color = ColorGreen
+	}
+	return color + arrow + " " + label + " " + beforeText + "→" + afterText + ColorReset
+}